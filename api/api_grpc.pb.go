@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// GameClient is the client API for Game service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GameClient interface {
+	// Start starts the game.
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
+	// Play makes a move.
+	Play(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*PlayResponse, error)
+	// Query gets the game as it is now.
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Subscribe streams updates, like a comms connection. It takes over the
+	// player's seat from any other connection.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Game_SubscribeClient, error)
+}
+
+type gameClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGameClient(cc grpc.ClientConnInterface) GameClient {
+	return &gameClient{cc}
+}
+
+func (c *gameClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error) {
+	out := new(StartResponse)
+	err := c.cc.Invoke(ctx, "/gogogo.api.Game/Start", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) Play(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*PlayResponse, error) {
+	out := new(PlayResponse)
+	err := c.cc.Invoke(ctx, "/gogogo.api.Game/Play", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/gogogo.api.Game/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Game_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Game_ServiceDesc.Streams[0], "/gogogo.api.Game/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gameSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Game_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type gameSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *gameSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GameServer is the server API for Game service.
+// All implementations must embed UnimplementedGameServer
+// for forward compatibility
+type GameServer interface {
+	// Start starts the game.
+	Start(context.Context, *StartRequest) (*StartResponse, error)
+	// Play makes a move.
+	Play(context.Context, *PlayRequest) (*PlayResponse, error)
+	// Query gets the game as it is now.
+	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+	// Subscribe streams updates, like a comms connection. It takes over the
+	// player's seat from any other connection.
+	Subscribe(*SubscribeRequest, Game_SubscribeServer) error
+	mustEmbedUnimplementedGameServer()
+}
+
+// UnimplementedGameServer must be embedded to have forward compatible implementations.
+type UnimplementedGameServer struct {
+}
+
+func (UnimplementedGameServer) Start(context.Context, *StartRequest) (*StartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (UnimplementedGameServer) Play(context.Context, *PlayRequest) (*PlayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Play not implemented")
+}
+func (UnimplementedGameServer) Query(context.Context, *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedGameServer) Subscribe(*SubscribeRequest, Game_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedGameServer) mustEmbedUnimplementedGameServer() {}
+
+// UnsafeGameServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GameServer will
+// result in compilation errors.
+type UnsafeGameServer interface {
+	mustEmbedUnimplementedGameServer()
+}
+
+func RegisterGameServer(s grpc.ServiceRegistrar, srv GameServer) {
+	s.RegisterService(&Game_ServiceDesc, srv)
+}
+
+func _Game_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gogogo.api.Game/Start",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).Start(ctx, req.(*StartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_Play_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).Play(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gogogo.api.Game/Play",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).Play(ctx, req.(*PlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gogogo.api.Game/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GameServer).Subscribe(m, &gameSubscribeServer{stream})
+}
+
+type Game_SubscribeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type gameSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *gameSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Game_ServiceDesc is the grpc.ServiceDesc for Game service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Game_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gogogo.api.Game",
+	HandlerType: (*GameServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Start",
+			Handler:    _Game_Start_Handler,
+		},
+		{
+			MethodName: "Play",
+			Handler:    _Game_Play_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _Game_Query_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Game_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/api.proto",
+}
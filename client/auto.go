@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+	gogame "github.com/undeconstructed/gogogo/go-game/lib"
+)
+
+const (
+	// autoDelay is the wait before each move, so that it can be followed
+	autoDelay = time.Second
+	// autoTries is how many failed moves to make before backing off
+	autoTries = 10
+	// autoBackoff is the wait before trying again, if nothing has changed, and
+	// doubles each time that nothing works
+	autoBackoff = 5 * time.Second
+	// autoMaxBackoff is the longest wait before trying again
+	autoMaxBackoff = time.Minute
+)
+
+// autoplay lets an AI play, instead of the UI, until the game ends.
+func (c *client) autoplay() error {
+	ai := gogame.NewAI(c.data, c.name, *c.auto, time.Now().UnixNano())
+
+	var state *gameState
+	updates := c.state.Listen(nil)
+	// for trying again when there's no news, e.g. after a run of failures
+	var retry <-chan time.Time
+	backoff := autoBackoff
+	for {
+		select {
+		case m := <-updates:
+			updates = c.state.Listen(m)
+			state = m.(*gameState)
+			c.printNews(state)
+
+			if state.update.Status == game.StatusWon {
+				fmt.Printf("%s wins\n", state.update.Winner)
+				return nil
+			}
+			backoff = autoBackoff
+		case <-retry:
+		case <-c.conn.Done():
+			return c.conn.Err()
+		}
+
+		retry = nil
+		if state == nil || state.playing != c.name {
+			continue
+		}
+
+		worked := c.autoTurn(ai, state)
+		// news should come first, but if not then the turn is looked at again
+		retry = time.After(backoff)
+		if !worked {
+			fmt.Printf("Nothing works, trying again in %v\n", backoff)
+			backoff *= 2
+			if backoff > autoMaxBackoff {
+				backoff = autoMaxBackoff
+			}
+		}
+	}
+}
+
+// autoTurn keeps making moves until something works, or there's nothing to
+// do, and says whether something worked.
+func (c *client) autoTurn(ai *gogame.AI, state *gameState) bool {
+	for i := 0; i < autoTries; i++ {
+		cmd := ai.Play(state.update)
+		if cmd == nil {
+			return false
+		}
+		time.Sleep(autoDelay)
+		fmt.Printf("%s» %s\n", c.name, cmd.Command)
+		_, err := c.conn.Play(context.Background(), *cmd)
+		if err == nil {
+			return true
+		}
+		fmt.Printf("Error: %v\n", err)
+	}
+	return false
+}
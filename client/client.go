@@ -2,16 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
+	"time"
 
-	"github.com/undeconstructed/gogogo/comms"
+	clib "github.com/undeconstructed/gogogo/client/client"
 	"github.com/undeconstructed/gogogo/game"
 	gogame "github.com/undeconstructed/gogogo/go-game/lib"
 
@@ -45,43 +45,36 @@ func col(s string) string {
 	}
 }
 
+const (
+	// requestTimeout is how long to wait for the server to answer
+	requestTimeout = 30 * time.Second
+	// reconnectTries is how many times to try to get a lost connection back
+	reconnectTries = 5
+	// reconnectDelay is the wait before each try
+	reconnectDelay = 3 * time.Second
+)
+
 type Client interface {
 	Run() error
 }
 
-func NewClient(data gogame.GameData, ccode string, server string) Client {
-	coreCh := make(chan interface{}, 100)
+// NewClient makes a client. If auto is set, it plays by itself at that level,
+// instead of asking what to do.
+func NewClient(data gogame.GameData, messages game.Catalogue, ccode string, server string, tlsConfig *tls.Config, auto *gogame.Level) Client {
 	return &client{
-		data:   data,
-		ccode:  ccode,
-		server: server,
-		coreCh: coreCh,
-		state:  NewBox(),
-		reqs:   map[string]RequestForServer{},
+		data:      data,
+		messages:  messages,
+		ccode:     ccode,
+		server:    server,
+		tlsConfig: tlsConfig,
+		auto:      auto,
+		state:     clib.NewBox(),
+		quitCh:    make(chan struct{}),
 	}
 }
 
-type toSend struct {
-	mtype string
-	data  interface{}
-}
-
-type TextFromServer struct {
-	Text string
-}
-
-type RequestForServer struct {
-	rtype string
-	rdata interface{}
-	rep   chan interface{}
-}
-
-type ResponseFromServer struct {
-	ID   string
-	Body interface{}
-}
-
 type gameState struct {
+	update  game.GameUpdate
 	playing string
 	players map[string]PlayerState
 	news    []game.Change
@@ -89,133 +82,49 @@ type gameState struct {
 }
 
 type client struct {
-	data   gogame.GameData
-	server string
-	ccode  string
-
-	gameId string
-	name   string
-	colour string
+	data      gogame.GameData
+	messages  game.Catalogue
+	server    string
+	tlsConfig *tls.Config
+	ccode     string
+	auto      *gogame.Level
 
-	// drives the main loop
-	coreCh chan interface{}
+	conn *clib.Client
+	name string
 
 	// boxed state, for the UI
-	state *Box
+	state *clib.Box
 
-	// the request system
-	reqNo int
-	reqs  map[string]RequestForServer
+	// closed when the UI ends
+	quitCh chan struct{}
 }
 
 func (c *client) Run() error {
-	// conn, err := net.Dial("unix", c.server)
-	conn, err := net.Dial("tcp", "localhost:1234")
+	conn, err := clib.Dial(context.Background(), c.server, c.ccode,
+		clib.WithTLS(c.tlsConfig),
+		clib.WithRequestTimeout(requestTimeout),
+		clib.WithReconnect(reconnectTries, reconnectDelay),
+		clib.WithHandler(clib.Handler{
+			Update: c.receiveUpdate,
+			Text: func(text string) {
+				fmt.Printf("server says: %s\n", text)
+			},
+			Reconnect: func(resumed bool) {
+				fmt.Printf("reconnected, resumed: %t\n", resumed)
+			},
+		}),
+	)
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
-	upStream := comms.NewEncoder(conn)
-	dnStream := comms.NewDecoder(conn)
+	c.conn = conn
+	c.name = conn.PlayerID()
 
-	err = upStream.Encode(fmt.Sprintf("connect:%s", c.ccode), comms.ConnectRequest{})
-	if err != nil {
-		return err
+	if c.auto != nil {
+		return c.autoplay()
 	}
-	res1, err := dnStream.Decode()
-	if err != nil {
-		return err
-	} else {
-		res := comms.ConnectResponse{}
-		err := comms.Decode(res1, &res)
-		if err != nil {
-			return err
-		}
-		err = game.ReError(res.Err)
-		if err != nil {
-			return err
-		}
-		c.gameId = res.GameID
-		c.name = res.PlayerID
-	}
-
-	upCh := make(chan interface{}, 1)
-	defer close(upCh)
-	downCh := make(chan comms.Message, 1)
-
-	go func() {
-		// read upCh, write to conn
-		for up := range upCh {
-			switch msg := up.(type) {
-			case comms.Message:
-				// send preformatted message
-				err := upStream.Send(msg)
-				if err != nil {
-					fmt.Printf("send error: %v\n", err)
-					return
-				}
-			case toSend:
-				// send anything
-				err := upStream.Encode(msg.mtype, msg.data)
-				if err != nil {
-					fmt.Printf("encode error: %v\n", err)
-					return
-				}
-			default:
-				fmt.Printf("cannot send: %#v\n", msg)
-			}
-		}
-	}()
-
-	go func() {
-		defer close(downCh)
-
-		// read conn, write to downCh
-		for {
-			msg, err := dnStream.Decode()
-			if err != nil {
-				if err != io.EOF {
-					fmt.Printf("gob decode error: %v\n", err)
-				}
-				c.coreCh <- nil
-				return
-			}
-			// fmt.Printf("received %s %s\n", msg.Head, string(msg.Data))
-
-			f := msg.Head.Fields()
-			switch f[0] {
-			case "turn":
-				about := TurnState{}
-				err := comms.Decode(msg, &about)
-				if err != nil {
-					fmt.Printf("bad turn message: %v\n", err)
-					continue
-				}
-				c.coreCh <- about
-			case "update":
-				about := GameUpdate{}
-				err := comms.Decode(msg, &about)
-				if err != nil {
-					fmt.Printf("bad update message: %v\n", err)
-					continue
-				}
-				c.coreCh <- about
-			case "text":
-				var text string
-				err := comms.Decode(msg, &text)
-				if err != nil {
-					fmt.Printf("bad text message: %v\n", err)
-					continue
-				}
-				c.coreCh <- TextFromServer{Text: text}
-			case "response":
-				id := f[1]
-				c.coreCh <- ResponseFromServer{ID: id, Body: msg.Data}
-			default:
-				fmt.Printf("junk from server: %v\n", f)
-			}
-		}
-	}()
 
 	stopUI, err := c.startUI()
 	if err != nil {
@@ -223,124 +132,65 @@ func (c *client) Run() error {
 	}
 	defer stopUI()
 
-	// this is the client's main loop
-	for in := range c.coreCh {
-		if in == nil {
-			fmt.Printf("nil in core\n")
-			break
-		}
-
-		switch msg := in.(type) {
-		case toSend:
-			// forward, unquestioning
-			upCh <- msg
-		case TextFromServer:
-			// TODO
-		case TurnState:
-			c.receiveTurn(msg)
-		case GameUpdate:
-			c.receiveUpdate(msg)
-		case RequestForServer:
-			reqID := strconv.Itoa(c.reqNo)
-			c.reqNo++
-			mtype := "request:" + reqID + ":" + msg.rtype
-			c.reqs[reqID] = msg
-			upCh <- toSend{mtype, msg.rdata}
-		case ResponseFromServer:
-			rr := c.reqs[msg.ID]
-			delete(c.reqs, msg.ID)
-			rr.rep <- msg.Body
-		default:
-			fmt.Printf("nonsense in core: %#v\n", in)
-		}
+	select {
+	case <-c.quitCh:
+		return nil
+	case <-conn.Done():
+		return conn.Err()
 	}
-
-	return nil
 }
 
-func (c *client) receiveTurn(turn TurnState) {
+func (c *client) receiveUpdate(update game.GameUpdate) {
 	var state gameState
 	if s, ok := c.state.Get().(*gameState); ok {
 		// copy the old state
 		state = *s
 	}
-	state.turn = &turn
-	c.state.Put(&state)
-}
-
-func (c *client) receiveUpdate(update GameUpdate) {
-	var state gameState
-	if s, ok := c.state.Get().(*gameState); ok {
-		// copy the old state
-		state = *s
-	}
-
-	if update.Playing != c.name {
-		// not our turn, will be get reset when a turn object arrives
-		state.turn = nil
-	}
 
+	state.update = update
 	state.playing = update.Playing
 
-	if state.players == nil {
-		state.players = map[string]PlayerState{}
-	}
-	for _, pl := range update.Players {
-		state.players[pl.Name] = pl
-	}
-
-	for _, news := range update.News {
-		state.news = append(state.news, news)
-	}
-
-	c.state.Put(&state)
-}
-
-func (c *client) doRequest(rtype string, rbody interface{}, resp interface{}) error {
-	rr := RequestForServer{rtype, rbody, make(chan interface{}, 1)}
-	c.coreCh <- rr
-	res := <-rr.rep
-	bytes := res.([]byte)
-	err := json.Unmarshal(bytes, resp)
+	global := gogame.GlobalState{}
+	err := json.Unmarshal(update.Global, &global)
 	if err != nil {
-		return err
+		fmt.Printf("bad global state: %v\n", err)
 	}
-	return nil
-}
 
-func (c *client) doGameStart() error {
-	res := game.StartResultJSON{}
-	err := c.doRequest("start", nil, &res)
-	if err != nil {
-		return err
+	players := map[string]PlayerState{}
+	for name, pl := range global.Players {
+		players[name] = PlayerState{Name: name, Colour: pl.Colour, Custom: pl}
 	}
-	if res.Err != nil {
-		return game.ReError(res.Err)
+	state.players = players
+
+	state.turn = nil
+	if update.Turn != nil {
+		turn := &TurnState{
+			Number: update.Turn.Number,
+			Player: update.Playing,
+			Can:    update.Turn.Can,
+			Must:   update.Turn.Must,
+		}
+		// custom is whatever the JSON decoder made of it
+		bs, _ := json.Marshal(update.Turn.Custom)
+		json.Unmarshal(bs, &turn.Custom)
+		state.turn = turn
 	}
-	return nil
-}
 
-func (c *client) doGamePlay(command game.Command) (json.RawMessage, error) {
-	res := game.PlayResultJSON{}
-	err := c.doRequest("play", command, &res)
-	if err != nil {
-		return nil, err
-	}
-	if res.Err != nil {
-		return nil, game.ReError(res.Err)
-	}
-	return res.Msg, nil
-}
+	state.news = append(state.news, update.News...)
 
-func (c *client) doGameQuery(cmd string, resp interface{}) error {
-	return c.doRequest("query:"+cmd, nil, resp)
+	c.state.Put(&state)
 }
 
 func (c *client) printNews(state *gameState) {
 	news := state.news
 	state.news = nil // UGHs
 	for _, u := range news {
-		fmt.Println(">", u)
+		what := c.messages.Describe(u, c.data.Name)
+		if u.Who == "" {
+			fmt.Println(">", what)
+		} else {
+			fmt.Println(">", u.Who, what)
+		}
 	}
 }
 
@@ -397,7 +247,7 @@ func (c *client) startUI() (func() error, error) {
 	go func() {
 		defer func() {
 			l.Close()
-			c.coreCh <- nil
+			close(c.quitCh)
 		}()
 		c.gameRepl(l)
 	}()
@@ -473,7 +323,7 @@ func (c *client) gameRepl(l *rl.Instance) error {
 
 	doPlayPrompt := func(s *gameState) {
 		player := c.name
-		colour := col(c.colour)
+		colour := col(s.players[player].Colour)
 		number := s.turn.Number
 
 		loc := "track"
@@ -551,11 +401,14 @@ func (c *client) gameRepl(l *rl.Instance) error {
 
 		switch cmd {
 		case "send":
-			c.coreCh <- toSend{mtype: "text", data: rest}
+			err := c.conn.Say(rest)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
 		case "follow":
 			follow = true
 		case "start":
-			err := c.doGameStart()
+			err := c.conn.Start(context.Background())
 			if err != nil {
 				errCode := game.Code(err)
 				if errCode != game.StatusAlreadyStarted {
@@ -581,7 +434,7 @@ func (c *client) gameRepl(l *rl.Instance) error {
 				c.printBank()
 			case "places":
 				about := []string{}
-				err := c.doGameQuery("places", &about)
+				err := c.conn.Query(context.Background(), "places", &about)
 				if err != nil {
 					fmt.Printf("error: %v\n", err)
 					continue
@@ -606,7 +459,7 @@ func (c *client) gameRepl(l *rl.Instance) error {
 				c.printPlace(name)
 			case "players":
 				about := []string{}
-				err := c.doGameQuery("players", &about)
+				err := c.conn.Query(context.Background(), "players", &about)
 				if err != nil {
 					fmt.Printf("error: %v\n", err)
 					continue
@@ -629,7 +482,7 @@ func (c *client) gameRepl(l *rl.Instance) error {
 			s := strings.ReplaceAll(rest, " ", ":")
 			cmd := game.CommandString(s)
 
-			res, err := c.doGamePlay(game.Command{Command: cmd})
+			res, err := c.conn.Play(context.Background(), game.Command{Command: cmd})
 			if err != nil {
 				errCode := game.Code(err)
 				if errCode == game.StatusBadRequest {
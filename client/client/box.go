@@ -1,9 +1,10 @@
-package main
+package client
 
 import (
 	"sync"
 )
 
+// Box holds a value, and lets goroutines wait for it to change.
 type Box struct {
 	l *sync.Mutex
 	c *sync.Cond
@@ -17,14 +18,19 @@ func NewBox() *Box {
 }
 
 func (b *Box) Put(v interface{}) {
+	b.l.Lock()
+	defer b.l.Unlock()
 	b.v = v
 	b.c.Broadcast()
 }
 
 func (b *Box) Get() interface{} {
+	b.l.Lock()
+	defer b.l.Unlock()
 	return b.v
 }
 
+// Wait blocks until the value is something other than seen.
 func (b *Box) Wait(seen interface{}) interface{} {
 	b.l.Lock()
 	defer b.l.Unlock()
@@ -34,6 +40,7 @@ func (b *Box) Wait(seen interface{}) interface{} {
 	return b.v
 }
 
+// Listen is Wait, as a channel.
 func (b *Box) Listen(seen interface{}) <-chan interface{} {
 	ch := make(chan interface{}, 1)
 	go func() {
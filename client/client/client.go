@@ -0,0 +1,593 @@
+// Package client is a library for playing on a gogogo server, over TCP or a
+// websocket. It knows nothing about any particular game; updates come with the
+// game's own state as raw JSON, for the caller to decode.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+const (
+	// pingInterval is how often the server is pinged
+	pingInterval = 20 * time.Second
+	// deadTimeout is how long the server can be silent before it is thought dead
+	deadTimeout = 60 * time.Second
+	// handshakeTimeout is how long the server has to answer a connect
+	handshakeTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNotConnected is for requests made while the connection is down.
+	ErrNotConnected = errors.New("not connected")
+	// ErrConnectionLost is for requests that will never be answered, because
+	// the connection was lost and the session could not be resumed.
+	ErrConnectionLost = errors.New("connection lost")
+	// ErrClosed is for anything done after the client has been closed.
+	ErrClosed = errors.New("client closed")
+)
+
+// Handler has callbacks for things the server sends without being asked. The
+// callbacks are made one at a time, in order, from a goroutine of their own,
+// so they can make requests. Any of them can be nil.
+type Handler struct {
+	// Update is called with each game update.
+	Update func(game.GameUpdate)
+	// Text is called with text from the server.
+	Text func(string)
+	// Reconnect is called when a lost connection is back.
+	Reconnect func(resumed bool)
+	// Close is called last of all, with the reason the client stopped.
+	Close func(error)
+}
+
+// Option is something that can be set on a client when dialling.
+type Option func(*Client)
+
+// WithTLS makes TCP connections use TLS. For websockets, use a wss:// address,
+// and this for anything other than the default config.
+func WithTLS(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// WithHandler sets the callbacks.
+func WithHandler(h Handler) Option {
+	return func(c *Client) {
+		c.handler = h
+	}
+}
+
+// WithRequestTimeout sets a timeout for requests made with a context that has
+// no deadline of its own.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithReconnect makes the client try to get a lost connection back, resuming
+// the session, before giving up.
+func WithReconnect(tries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.reconnectTries = tries
+		c.reconnectDelay = delay
+	}
+}
+
+// Client is a connection to a game, as one player.
+type Client struct {
+	addr      string
+	code      string
+	tlsConfig *tls.Config
+	handler   Handler
+
+	requestTimeout time.Duration
+	reconnectTries int
+	reconnectDelay time.Duration
+
+	gameID   string
+	playerID string
+
+	// drives the main loop
+	coreCh chan interface{}
+	// feeds the handler
+	eventCh chan interface{}
+	// closed when the main loop has stopped, after which err is set
+	doneCh chan struct{}
+	err    error
+}
+
+// Dial connects to a server, with a connect code. Addresses starting ws:// or
+// wss:// are websocket URLs, e.g. ws://localhost:1235/ws, anything else is a
+// TCP host:port.
+func Dial(ctx context.Context, addr, code string, opts ...Option) (*Client, error) {
+	c := &Client{
+		addr:    addr,
+		code:    code,
+		coreCh:  make(chan interface{}, 100),
+		eventCh: make(chan interface{}),
+		doneCh:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	t, res, err := dial(ctx, addr, c.tlsConfig, code, comms.ConnectRequest{Patches: true})
+	if err != nil {
+		return nil, err
+	}
+
+	c.gameID = res.GameID
+	c.playerID = res.PlayerID
+
+	go c.events()
+	go c.run(t, res.Resume)
+
+	return c, nil
+}
+
+// GameID is the ID of the game this client is in.
+func (c *Client) GameID() string {
+	return c.gameID
+}
+
+// PlayerID is the ID of the player this client is.
+func (c *Client) PlayerID() string {
+	return c.playerID
+}
+
+// Done is closed when the client has stopped.
+func (c *Client) Done() <-chan struct{} {
+	return c.doneCh
+}
+
+// Err is why the client stopped, once Done is closed.
+func (c *Client) Err() error {
+	select {
+	case <-c.doneCh:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close disconnects, and stops the client.
+func (c *Client) Close() error {
+	select {
+	case c.coreCh <- closeMsg{}:
+	case <-c.doneCh:
+	}
+	<-c.doneCh
+	return nil
+}
+
+// Start starts the game.
+func (c *Client) Start(ctx context.Context) error {
+	res := game.StartResultJSON{}
+	err := c.Request(ctx, "start", nil, &res)
+	if err != nil {
+		return err
+	}
+	return game.ReError(res.Err)
+}
+
+// Play sends a command, and returns whatever the game said about it.
+func (c *Client) Play(ctx context.Context, command game.Command) (json.RawMessage, error) {
+	res := game.PlayResultJSON{}
+	err := c.Request(ctx, "play", command, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Err != nil {
+		return nil, game.ReError(res.Err)
+	}
+	return res.Msg, nil
+}
+
+// Query asks the game something, decoding the answer into resp.
+func (c *Client) Query(ctx context.Context, query string, resp interface{}) error {
+	return c.Request(ctx, "query:"+query, nil, resp)
+}
+
+// Say sends some text to the other players.
+func (c *Client) Say(text string) error {
+	msg, err := comms.Encode("text", text)
+	if err != nil {
+		return err
+	}
+	rep := make(chan error, 1)
+	select {
+	case c.coreCh <- sendMsg{msg, rep}:
+	case <-c.doneCh:
+		return ErrClosed
+	}
+	return <-rep
+}
+
+// Request sends a request of any type, and waits for the response, which is
+// decoded into resp, unless resp is nil.
+func (c *Client) Request(ctx context.Context, rtype string, body interface{}, resp interface{}) error {
+	if _, ok := ctx.Deadline(); !ok && c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	rep := make(chan reply, 1)
+	select {
+	case c.coreCh <- requestMsg{rtype, body, rep}:
+	case <-c.doneCh:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case r := <-rep:
+		if r.err != nil {
+			return r.err
+		}
+		if resp == nil {
+			return nil
+		}
+		return json.Unmarshal(r.data, resp)
+	case <-ctx.Done():
+		// forget it, so that a late response is dropped
+		select {
+		case c.coreCh <- cancelMsg{rep}:
+		case <-c.doneCh:
+		}
+		return ctx.Err()
+	}
+}
+
+// core messages
+
+type closeMsg struct{}
+
+type sendMsg struct {
+	msg comms.Message
+	rep chan error
+}
+
+type requestMsg struct {
+	rtype string
+	body  interface{}
+	rep   chan reply
+}
+
+type reply struct {
+	data json.RawMessage
+	err  error
+}
+
+type cancelMsg struct {
+	rep chan reply
+}
+
+type receivedMsg struct {
+	t   transport
+	msg comms.Message
+}
+
+type lostMsg struct {
+	t   transport
+	err error
+}
+
+type reconnectedMsg struct {
+	t   transport
+	res comms.ConnectResponse
+}
+
+type reconnectFailedMsg struct {
+	err error
+}
+
+// handler events
+
+type updateEvent game.GameUpdate
+type textEvent string
+type reconnectEvent bool
+type closeEvent struct{ err error }
+
+// run is the client's main loop. It owns the connection and the request
+// system, and queues events for the handler.
+func (c *Client) run(t transport, resume string) {
+	var lastSeq uint32
+
+	// the last update, as JSON without news, for patches to apply to
+	var base []byte
+	var baseSeq uint32
+	// news from patches that couldn't be applied, while waiting for a snapshot
+	var lostNews []game.Change
+	snapshotting := false
+
+	reqNo := 0
+	reqs := map[string]chan reply{}
+
+	// events are queued here, so that a slow handler never holds up the loop
+	var events []interface{}
+
+	go c.read(t)
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	send := func(msg comms.Message) error {
+		if t == nil {
+			return ErrNotConnected
+		}
+		err := t.Send(msg)
+		if err != nil {
+			// the reader will notice and report it lost
+			t.Close()
+		}
+		return err
+	}
+
+	failAll := func(err error) {
+		for id, rep := range reqs {
+			delete(reqs, id)
+			rep <- reply{err: err}
+		}
+	}
+
+	var err error
+
+loop:
+	for {
+		var eventOut chan interface{}
+		var nextEvent interface{}
+		if len(events) > 0 {
+			eventOut = c.eventCh
+			nextEvent = events[0]
+		}
+
+		select {
+		case eventOut <- nextEvent:
+			events = events[1:]
+		case <-ticker.C:
+			ping, _ := comms.Encode(comms.TypePing, nil)
+			send(ping)
+		case in := <-c.coreCh:
+			switch msg := in.(type) {
+			case closeMsg:
+				err = ErrClosed
+				break loop
+			case sendMsg:
+				msg.rep <- send(msg.msg)
+			case requestMsg:
+				if t == nil {
+					msg.rep <- reply{err: ErrNotConnected}
+					continue
+				}
+				reqID := strconv.Itoa(reqNo)
+				reqNo++
+				cmsg, err := comms.Encode("request:"+reqID+":"+msg.rtype, msg.body)
+				if err != nil {
+					msg.rep <- reply{err: err}
+					continue
+				}
+				reqs[reqID] = msg.rep
+				err = send(cmsg)
+				if err != nil {
+					delete(reqs, reqID)
+					msg.rep <- reply{err: err}
+				}
+			case cancelMsg:
+				for id, rep := range reqs {
+					if rep == msg.rep {
+						delete(reqs, id)
+					}
+				}
+			case receivedMsg:
+				if msg.t != t {
+					// from an old connection
+					continue
+				}
+				if msg.msg.Seq != 0 {
+					lastSeq = msg.msg.Seq
+				}
+				f := msg.msg.Head.Fields()
+				switch f[0] {
+				case comms.TypePing:
+					pong, _ := comms.Encode(comms.TypePong, nil)
+					send(pong)
+				case comms.TypePong:
+					// server is alive
+				case "response":
+					if len(f) < 2 {
+						continue
+					}
+					rep, ok := reqs[f[1]]
+					if !ok {
+						continue
+					}
+					delete(reqs, f[1])
+					rep <- reply{data: msg.msg.Data}
+				case "update":
+					update := game.GameUpdate{}
+					err := comms.Decode(msg.msg, &update)
+					if err != nil {
+						continue
+					}
+					news := update.News
+					update.News = nil
+					base, _ = json.Marshal(update)
+					baseSeq = msg.msg.Seq
+					update.News = append(lostNews, news...)
+					lostNews = nil
+					snapshotting = false
+					events = append(events, updateEvent(update))
+				case "patch":
+					patch := game.GameUpdatePatch{}
+					err := comms.Decode(msg.msg, &patch)
+					if err != nil {
+						continue
+					}
+					update, doc, err := applyPatch(base, baseSeq, patch)
+					if err != nil {
+						// lost track, so get everything again, keeping the news
+						lostNews = append(lostNews, patch.News...)
+						if !snapshotting {
+							snapshot, _ := comms.Encode("request:snapshot:snapshot", nil)
+							send(snapshot)
+							snapshotting = true
+						}
+						continue
+					}
+					base, baseSeq = doc, msg.msg.Seq
+					events = append(events, updateEvent(update))
+				case "text":
+					var text string
+					err := comms.Decode(msg.msg, &text)
+					if err != nil {
+						continue
+					}
+					events = append(events, textEvent(text))
+				}
+			case lostMsg:
+				if msg.t != t {
+					continue
+				}
+				t.Close()
+				t = nil
+				if c.reconnectTries <= 0 {
+					err = msg.err
+					break loop
+				}
+				go c.reconnect(resume, lastSeq)
+			case reconnectedMsg:
+				t = msg.t
+				resume = msg.res.Resume
+				if !msg.res.Resumed {
+					// anything outstanding is never going to be answered
+					lastSeq = 0
+					failAll(ErrConnectionLost)
+				}
+				go c.read(t)
+				events = append(events, reconnectEvent(msg.res.Resumed))
+			case reconnectFailedMsg:
+				err = msg.err
+				break loop
+			}
+		}
+	}
+
+	if t != nil {
+		t.Close()
+	}
+
+	c.err = err
+	close(c.doneCh)
+
+	// nothing can be waiting to send now, so safe to answer everything
+	failAll(ErrClosed)
+
+	// the handler gets anything that was queued, then the end
+	for _, e := range events {
+		c.eventCh <- e
+	}
+	c.eventCh <- closeEvent{err}
+	close(c.eventCh)
+}
+
+// applyPatch applies a patch to the last update, which must be the one that
+// the patch is against.
+func applyPatch(base []byte, baseSeq uint32, patch game.GameUpdatePatch) (game.GameUpdate, []byte, error) {
+	update := game.GameUpdate{}
+	if base == nil || patch.Base != baseSeq {
+		return update, nil, errors.New("patch is not against the last update")
+	}
+	doc, err := comms.ApplyMergePatch(base, patch.Patch)
+	if err != nil {
+		return update, nil, err
+	}
+	err = json.Unmarshal(doc, &update)
+	if err != nil {
+		return update, nil, err
+	}
+	update.News = patch.News
+	return update, doc, nil
+}
+
+// read reads a connection until it fails.
+func (c *Client) read(t transport) {
+	for {
+		msg, err := t.Recv(deadTimeout)
+		if err != nil {
+			select {
+			case c.coreCh <- lostMsg{t, err}:
+			case <-c.doneCh:
+			}
+			return
+		}
+		select {
+		case c.coreCh <- receivedMsg{t, msg}:
+		case <-c.doneCh:
+			return
+		}
+	}
+}
+
+// reconnect tries to connect again, resuming the session.
+func (c *Client) reconnect(resume string, lastSeq uint32) {
+	req := comms.ConnectRequest{Resume: resume, LastSeq: lastSeq, Patches: true}
+
+	var err error
+	for i := 0; i < c.reconnectTries; i++ {
+		time.Sleep(c.reconnectDelay)
+		ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+		t, res, err1 := dial(ctx, c.addr, c.tlsConfig, c.code, req)
+		cancel()
+		if err1 != nil {
+			err = err1
+			continue
+		}
+		select {
+		case c.coreCh <- reconnectedMsg{t, res}:
+		case <-c.doneCh:
+			// closed while reconnecting
+			t.Close()
+		}
+		return
+	}
+	select {
+	case c.coreCh <- reconnectFailedMsg{err}:
+	case <-c.doneCh:
+	}
+}
+
+// events makes the handler callbacks.
+func (c *Client) events() {
+	for e := range c.eventCh {
+		switch e := e.(type) {
+		case updateEvent:
+			if c.handler.Update != nil {
+				c.handler.Update(game.GameUpdate(e))
+			}
+		case textEvent:
+			if c.handler.Text != nil {
+				c.handler.Text(string(e))
+			}
+		case reconnectEvent:
+			if c.handler.Reconnect != nil {
+				c.handler.Reconnect(bool(e))
+			}
+		case closeEvent:
+			if c.handler.Close != nil {
+				c.handler.Close(e.err)
+			}
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// fakeServer accepts one TCP client, and answers requests with whatever
+// answer says, or not at all if it returns nil.
+func fakeServer(t *testing.T, answer func(rtype string) interface{}) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		enc := comms.NewEncoder(conn)
+		dec := comms.NewDecoder(conn)
+
+		_, err = dec.Decode()
+		if err != nil {
+			return
+		}
+		enc.Encode("connected", comms.ConnectResponse{GameID: "g", PlayerID: "p"})
+		enc.Encode("update", game.GameUpdate{Playing: "p"})
+
+		for {
+			msg, err := dec.Decode()
+			if err != nil {
+				return
+			}
+			f := msg.Head.Fields()
+			if f[0] != "request" {
+				continue
+			}
+			res := answer(f[2])
+			if res == nil {
+				continue
+			}
+			enc.Encode("response:"+f[1], res)
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func TestClient_request(t *testing.T) {
+	addr := fakeServer(t, func(rtype string) interface{} {
+		return []string{rtype}
+	})
+
+	updates := make(chan game.GameUpdate, 1)
+	c, err := Dial(context.Background(), addr, "code", WithHandler(Handler{
+		Update: func(u game.GameUpdate) { updates <- u },
+	}))
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer c.Close()
+
+	if c.GameID() != "g" || c.PlayerID() != "p" {
+		t.Errorf("bad ids: %s %s", c.GameID(), c.PlayerID())
+	}
+
+	var res []string
+	err = c.Query(context.Background(), "places", &res)
+	if err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if len(res) != 1 || res[0] != "query" {
+		t.Errorf("bad response: %v", res)
+	}
+
+	select {
+	case u := <-updates:
+		if u.Playing != "p" {
+			t.Errorf("bad update: %v", u)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("no update")
+	}
+}
+
+func TestClient_timeout(t *testing.T) {
+	addr := fakeServer(t, func(rtype string) interface{} {
+		return nil
+	})
+
+	c, err := Dial(context.Background(), addr, "code", WithRequestTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer c.Close()
+
+	err = c.Start(context.Background())
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected timeout, got: %v", err)
+	}
+}
+
+func TestClient_closed(t *testing.T) {
+	addr := fakeServer(t, func(rtype string) interface{} {
+		return nil
+	})
+
+	c, err := Dial(context.Background(), addr, "code")
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.Start(context.Background())
+	}()
+	// let the request get going
+	time.Sleep(10 * time.Millisecond)
+	c.Close()
+
+	if err := <-errCh; err != ErrClosed {
+		t.Errorf("expected closed, got: %v", err)
+	}
+	if c.Err() != ErrClosed {
+		t.Errorf("bad err: %v", c.Err())
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	base := []byte(`{"status":"inprogress","playing":"a","turnNumber":1,"global":{"x":1,"y":2}}`)
+
+	patch := game.GameUpdatePatch{
+		Base:  5,
+		News:  []game.Change{{Who: "a", What: "moves"}},
+		Patch: []byte(`{"playing":"b","turnNumber":2,"global":{"y":3}}`),
+	}
+	update, doc, err := applyPatch(base, 5, patch)
+	if err != nil {
+		t.Fatalf("apply error: %v", err)
+	}
+	if update.Playing != "b" || update.TurnNumber != 2 || update.Status != game.StatusInProgress || len(update.News) != 1 {
+		t.Errorf("bad update: %v", update)
+	}
+	if string(update.Global) != `{"x":1,"y":3}` {
+		t.Errorf("bad global: %s", update.Global)
+	}
+	if doc == nil {
+		t.Errorf("no new base")
+	}
+
+	// only against the right update
+	_, _, err = applyPatch(base, 4, patch)
+	if err == nil {
+		t.Errorf("applied to wrong base")
+	}
+	_, _, err = applyPatch(nil, 5, patch)
+	if err == nil {
+		t.Errorf("applied to nothing")
+	}
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+
+	"nhooyr.io/websocket"
+)
+
+// transport is one connection to the server, already past the handshake.
+type transport interface {
+	Send(msg comms.Message) error
+	// Recv waits for the next message, for up to timeout.
+	Recv(timeout time.Duration) (comms.Message, error)
+	Close() error
+}
+
+// dial connects to addr, and does the connect handshake. Addresses starting
+// ws:// or wss:// are websockets, anything else is a TCP host:port.
+func dial(ctx context.Context, addr string, tlsConfig *tls.Config, code string, req comms.ConnectRequest) (transport, comms.ConnectResponse, error) {
+	var t transport
+	var err error
+	if strings.HasPrefix(addr, "ws://") || strings.HasPrefix(addr, "wss://") {
+		t, err = dialWs(ctx, addr, tlsConfig, code, req)
+	} else {
+		t, err = dialTCP(ctx, addr, tlsConfig, code, req)
+	}
+	if err != nil {
+		return nil, comms.ConnectResponse{}, err
+	}
+
+	msg, err := t.Recv(handshakeTimeout)
+	if err != nil {
+		t.Close()
+		return nil, comms.ConnectResponse{}, err
+	}
+	if msg.Type() != "connected" {
+		t.Close()
+		return nil, comms.ConnectResponse{}, fmt.Errorf("expected connected, got %s", msg.Head)
+	}
+	res := comms.ConnectResponse{}
+	err = comms.Decode(msg, &res)
+	if err != nil {
+		t.Close()
+		return nil, comms.ConnectResponse{}, err
+	}
+	if res.Err != nil {
+		t.Close()
+		return nil, res, game.ReError(res.Err)
+	}
+
+	return t, res, nil
+}
+
+type tcpTransport struct {
+	conn net.Conn
+	enc  *comms.Encoder
+	dec  *comms.Decoder
+}
+
+func dialTCP(ctx context.Context, addr string, tlsConfig *tls.Config, code string, req comms.ConnectRequest) (transport, error) {
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		d := &tls.Dialer{Config: tlsConfig}
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	} else {
+		d := &net.Dialer{}
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tcpTransport{
+		conn: conn,
+		enc:  comms.NewEncoder(conn),
+		dec:  comms.NewDecoder(conn),
+	}
+
+	// the TCP gateway wants the request as the first message
+	err = t.enc.Encode("connect:"+code, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *tcpTransport) Send(msg comms.Message) error {
+	return t.enc.Send(msg)
+}
+
+func (t *tcpTransport) Recv(timeout time.Duration) (comms.Message, error) {
+	t.conn.SetReadDeadline(time.Now().Add(timeout))
+	return t.dec.Decode()
+}
+
+func (t *tcpTransport) Close() error {
+	return t.conn.Close()
+}
+
+type wsTransport struct {
+	ws *websocket.Conn
+}
+
+func dialWs(ctx context.Context, addr string, tlsConfig *tls.Config, code string, req comms.ConnectRequest) (transport, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// the web gateway wants the request in the query
+	q := u.Query()
+	q.Set("c", code)
+	if req.Resume != "" {
+		q.Set("resume", req.Resume)
+		q.Set("seq", strconv.FormatUint(uint64(req.LastSeq), 10))
+	}
+	if req.Patches {
+		q.Set("patches", "true")
+	}
+	u.RawQuery = q.Encode()
+
+	opts := &websocket.DialOptions{
+		Subprotocols: []string{"comms"},
+	}
+	if tlsConfig != nil {
+		opts.HTTPClient = &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		}
+	}
+
+	ws, _, err := websocket.Dial(ctx, u.String(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wsTransport{ws}, nil
+}
+
+func (t *wsTransport) Send(msg comms.Message) error {
+	bytes, err := comms.MarshalJSONMessage(msg)
+	if err != nil {
+		return err
+	}
+	return t.ws.Write(context.Background(), websocket.MessageText, bytes)
+}
+
+func (t *wsTransport) Recv(timeout time.Duration) (comms.Message, error) {
+	// a timeout here closes the socket, but that is the point anyway
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	typ, r, err := t.ws.Reader(ctx)
+	if err != nil {
+		return comms.Message{}, err
+	}
+	if typ != websocket.MessageText {
+		return comms.Message{}, fmt.Errorf("server sent a %v", typ)
+	}
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return comms.Message{}, err
+	}
+	return comms.UnmarshalJSONMessage(bytes)
+}
+
+func (t *wsTransport) Close() error {
+	return t.ws.Close(websocket.StatusNormalClosure, "bye")
+}
@@ -1,8 +1,16 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
+	"github.com/undeconstructed/gogogo/game"
+	goassets "github.com/undeconstructed/gogogo/go-game"
 	gogame "github.com/undeconstructed/gogogo/go-game/lib"
 
 	"github.com/rs/zerolog"
@@ -13,14 +21,80 @@ func main() {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
-	ccode := os.Args[1]
+	pserver := flag.String("server", "localhost:1234", "server address")
+	ptls := flag.Bool("tls", false, "connect with TLS")
+	pca := flag.String("ca", "", "CA certificate file to trust, implies -tls")
+	pauto := flag.String("auto", "", "play by itself, at level easy, normal or hard")
+	plang := flag.String("lang", langFromEnv(), "language for news, e.g. en or de")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <connect code>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	ccode := flag.Arg(0)
+
+	var auto *gogame.Level
+	if *pauto != "" {
+		level, err := gogame.ParseLevel(*pauto)
+		if err != nil {
+			log.Error().Err(err).Msg("bad auto level")
+			os.Exit(2)
+		}
+		auto = &level
+	}
+
+	var tlsConfig *tls.Config
+	if *ptls || *pca != "" {
+		tlsConfig = &tls.Config{}
+		if *pca != "" {
+			pem, err := ioutil.ReadFile(*pca)
+			if err != nil {
+				log.Error().Err(err).Msg("cannot read CA file")
+				os.Exit(1)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				log.Error().Msg("no certificates in CA file")
+				os.Exit(1)
+			}
+			tlsConfig.RootCAs = pool
+		}
+	}
 
-	data := gogame.LoadJson(".")
+	data := gogame.LoadJsonFS(goassets.FS)
 
-	client := NewClient(data, ccode, "game.socket")
-	err := client.Run()
+	messages, err := game.ReadCatalogue(goassets.FS, *plang)
+	if err != nil {
+		log.Warn().Err(err).Msgf("no messages for language: %s", *plang)
+		messages = data.Messages
+	}
+
+	client := NewClient(data, messages, ccode, *pserver, tlsConfig, auto)
+	err = client.Run()
 	if err != nil {
 		log.Info().Err(err).Msg("client ended")
 		os.Exit(1)
 	}
 }
+
+// langFromEnv guesses the user's language from the locale, e.g. "de" from
+// LANG=de_DE.UTF-8.
+func langFromEnv() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		parts := strings.FieldsFunc(os.Getenv(env), func(r rune) bool { return r == '_' || r == '.' || r == '@' })
+		if len(parts) == 0 {
+			continue
+		}
+		lang := strings.ToLower(parts[0])
+		if lang == "c" || lang == "posix" {
+			break
+		}
+		return lang
+	}
+	return "en"
+}
@@ -1,15 +1,10 @@
 package main
 
 import (
-	"github.com/undeconstructed/gogogo/game"
 	gogame "github.com/undeconstructed/gogogo/go-game/lib"
 )
 
-type GameUpdate struct {
-	News []game.Change `json:"news"`
-	GameState
-}
-
+// TurnState is the game's turn object, with go's custom part decoded.
 type TurnState struct {
 	Number int    `json:"number"`
 	Player string `json:"player"`
@@ -20,13 +15,7 @@ type TurnState struct {
 	Custom gogame.TurnState `json:"custom"`
 }
 
-type GameState struct {
-	Status  game.GameStatus `json:"status"`
-	Playing string          `json:"playing"`
-	Winner  string          `json:"winner"`
-	Players []PlayerState   `json:"players"`
-}
-
+// PlayerState is a player, from go's global state.
 type PlayerState struct {
 	Name   string `json:"name"`
 	Colour string `json:"colour"`
@@ -4,10 +4,16 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 )
 
+// CodeUnknown is the code for errors that don't say what they are.
+const CodeUnknown = "?"
+
+// ErrorCoder is an error that says what it is, with a code that clients can
+// check.
 type ErrorCoder interface {
 	ErrorCode() string
 }
@@ -24,7 +30,7 @@ func WrapError(err error) *CommsError {
 	if ec, ok := err.(ErrorCoder); ok {
 		return &CommsError{ec.ErrorCode(), err}
 	}
-	return &CommsError{"TODO", err}
+	return &CommsError{CodeUnknown, err}
 }
 
 func (e *CommsError) ErrorCode() string { return e.Code }
@@ -57,6 +63,14 @@ func (e *CommsError) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Message types that are part of the protocol itself, rather than of a game.
+const (
+	// TypePing asks the other end to reply with a pong.
+	TypePing = "ping"
+	// TypePong is the reply to a ping.
+	TypePong = "pong"
+)
+
 type Head string
 
 func headFromType(mtype string) Head {
@@ -83,36 +97,73 @@ func (h Head) Bytes() []byte {
 	return []byte(h)
 }
 
+// ContentType says how the data in a message is encoded.
+type ContentType byte
+
+const (
+	// ContentBinary is opaque bytes.
+	ContentBinary ContentType = 0
+	// ContentJSON is a JSON document.
+	ContentJSON ContentType = 1
+	// ContentText is UTF-8 text.
+	ContentText ContentType = 2
+)
+
+func (c ContentType) String() string {
+	switch c {
+	case ContentBinary:
+		return "binary"
+	case ContentJSON:
+		return "json"
+	case ContentText:
+		return "text"
+	default:
+		return fmt.Sprintf("content(%d)", byte(c))
+	}
+}
+
 type Message struct {
-	Head Head
-	Data []byte
+	Head    Head
+	Content ContentType
+	Data    []byte
+	// Seq numbers messages in a session, 0 means not numbered.
+	Seq uint32
 }
 
 func (m Message) Type() string {
 	return m.Head.Fields()[0]
 }
 
+// Encode makes a message. Byte slices are taken as already serial binary
+// data, anything else is encoded to JSON.
 func Encode(mtype string, message interface{}) (Message, error) {
-	var data []byte
 	if mdata, ok := message.([]byte); ok {
 		// already serial
-		data = mdata
-	} else {
-		// encode to JSON
-		jdata, err := json.Marshal(message)
-		if err != nil {
-			return Message{}, err
-		}
-		data = jdata
+		return Message{
+			Head:    headFromType(mtype),
+			Content: ContentBinary,
+			Data:    mdata,
+		}, nil
+	}
+
+	// encode to JSON
+	jdata, err := json.Marshal(message)
+	if err != nil {
+		return Message{}, err
 	}
 
 	return Message{
-		Head: headFromType(mtype),
-		Data: data,
+		Head:    headFromType(mtype),
+		Content: ContentJSON,
+		Data:    jdata,
 	}, nil
 }
 
+// Decode decodes the data of a JSON message.
 func Decode(m Message, v interface{}) error {
+	if m.Content != ContentJSON {
+		return fmt.Errorf("cannot decode %v as json", m.Content)
+	}
 	err := json.Unmarshal(m.Data, v)
 	if err != nil {
 		return err
@@ -120,16 +171,42 @@ func Decode(m Message, v interface{}) error {
 	return nil
 }
 
+const (
+	// FrameVersion is the version of the framing written by Encoder.
+	FrameVersion = 2
+	// DefaultMaxSize is the default limit on head plus data in one message.
+	DefaultMaxSize = 1 << 20
+
+	// version 1 header is version(1) content(1) reserved(2) headLength(4) dataLength(4)
+	frameHeaderSizeV1 = 12
+	// version 2 header is the version 1 header, then seq(4)
+	frameHeaderSizeV2 = 16
+)
+
+var (
+	// ErrBadVersion is for frames from some other version of the protocol.
+	ErrBadVersion = errors.New("bad frame version")
+	// ErrTooLarge is for messages over the size limit.
+	ErrTooLarge = errors.New("message too large")
+)
+
 type Encoder struct {
-	out io.Writer
+	out     io.Writer
+	maxSize int
 }
 
 func NewEncoder(out io.Writer) *Encoder {
 	return &Encoder{
-		out: out,
+		out:     out,
+		maxSize: DefaultMaxSize,
 	}
 }
 
+// SetMaxSize changes the limit on the size of messages that will be sent.
+func (enc *Encoder) SetMaxSize(n int) {
+	enc.maxSize = n
+}
+
 func (enc *Encoder) Encode(mtype string, e interface{}) error {
 	msg, err := Encode(mtype, e)
 	if err != nil {
@@ -140,77 +217,169 @@ func (enc *Encoder) Encode(mtype string, e interface{}) error {
 }
 
 func (enc *Encoder) Send(msg Message) error {
-	headLength := uint32(msg.Head.Length())
-	dataLength := uint32(len(msg.Data))
-	length := headLength + dataLength + 12
-
-	sizeBuf := make([]byte, 12)
-	binary.BigEndian.PutUint32(sizeBuf, length)
-	binary.BigEndian.PutUint32(sizeBuf[4:], headLength)
-	binary.BigEndian.PutUint32(sizeBuf[8:], dataLength)
-
-	_, err := enc.out.Write(sizeBuf)
-	if err != nil {
-		return err
-	}
-	_, err = enc.out.Write(msg.Head.Bytes())
-	if err != nil {
-		return err
-	}
-	_, err = enc.out.Write(msg.Data)
-	if err != nil {
-		return err
+	headLength := msg.Head.Length()
+	dataLength := len(msg.Data)
+	if headLength+dataLength > enc.maxSize {
+		return ErrTooLarge
 	}
 
-	return nil
+	// one write, so that the frame is not split up by the writer
+	buf := make([]byte, frameHeaderSizeV2, frameHeaderSizeV2+headLength+dataLength)
+	buf[0] = FrameVersion
+	buf[1] = byte(msg.Content)
+	binary.BigEndian.PutUint32(buf[4:], uint32(headLength))
+	binary.BigEndian.PutUint32(buf[8:], uint32(dataLength))
+	binary.BigEndian.PutUint32(buf[12:], msg.Seq)
+	buf = append(buf, msg.Head.Bytes()...)
+	buf = append(buf, msg.Data...)
+
+	_, err := enc.out.Write(buf)
+	return err
 }
 
 type Decoder struct {
-	in io.Reader
+	in      io.Reader
+	maxSize int
 }
 
 func NewDecoder(in io.Reader) *Decoder {
 	return &Decoder{
-		in: in,
+		in:      in,
+		maxSize: DefaultMaxSize,
 	}
 }
 
+// SetMaxSize changes the limit on the size of messages that will be accepted.
+func (dec *Decoder) SetMaxSize(n int) {
+	dec.maxSize = n
+}
+
+// Decode reads one whole message. After any error other than io.EOF, the
+// stream should be considered broken.
 func (dec *Decoder) Decode() (Message, error) {
-	sizeBuf := make([]byte, 12)
-	_, err := dec.in.Read(sizeBuf)
+	sizeBuf := make([]byte, frameHeaderSizeV2)
+	_, err := io.ReadFull(dec.in, sizeBuf[:frameHeaderSizeV1])
 	if err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return Message{}, errors.New("truncated frame header")
+		}
 		return Message{}, err
 	}
 
-	length := binary.BigEndian.Uint32(sizeBuf)
-	typeLength := binary.BigEndian.Uint32(sizeBuf[4:])
+	var seq uint32
+	switch sizeBuf[0] {
+	case 1:
+		// no seq
+	case 2:
+		_, err := io.ReadFull(dec.in, sizeBuf[frameHeaderSizeV1:])
+		if err != nil {
+			return Message{}, errors.New("truncated frame header")
+		}
+		seq = binary.BigEndian.Uint32(sizeBuf[12:])
+	default:
+		return Message{}, ErrBadVersion
+	}
+
+	content := ContentType(sizeBuf[1])
+	headLength := binary.BigEndian.Uint32(sizeBuf[4:])
 	dataLength := binary.BigEndian.Uint32(sizeBuf[8:])
 
-	if length != typeLength+dataLength+12 {
-		return Message{}, errors.New("bad data in pipe")
+	// check before allocating anything, and in 64 bits so nothing overflows
+	if uint64(headLength)+uint64(dataLength) > uint64(dec.maxSize) {
+		return Message{}, ErrTooLarge
 	}
 
-	typeBuf := make([]byte, typeLength)
-	_, err = dec.in.Read(typeBuf)
+	buf := make([]byte, headLength+dataLength)
+	_, err = io.ReadFull(dec.in, buf)
 	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return Message{}, errors.New("truncated frame")
+		}
 		return Message{}, err
 	}
 
-	dataBuf := make([]byte, dataLength)
-	_, err = dec.in.Read(dataBuf)
+	return Message{
+		Head:    headFromBytes(buf[:headLength]),
+		Content: content,
+		Data:    buf[headLength:],
+		Seq:     seq,
+	}, nil
+}
+
+// JSONMessage is a Message as a JSON object, as used in websocket text
+// frames. Data is JSON unless Type says otherwise, in which case it is a
+// string of text, or base64 for binary.
+type JSONMessage struct {
+	Head string          `json:"head"`
+	Type string          `json:"type,omitempty"`
+	Seq  uint32          `json:"seq,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// MarshalJSONMessage turns a Message into a JSONMessage, as bytes.
+func MarshalJSONMessage(msg Message) ([]byte, error) {
+	jmsg := JSONMessage{
+		Head: string(msg.Head),
+		Seq:  msg.Seq,
+	}
+
+	switch msg.Content {
+	case ContentJSON:
+		jmsg.Data = json.RawMessage(msg.Data)
+	case ContentText:
+		jmsg.Type = ContentText.String()
+		jmsg.Data, _ = json.Marshal(string(msg.Data))
+	default:
+		// base64, as that is how []byte becomes JSON
+		jmsg.Type = ContentBinary.String()
+		jmsg.Data, _ = json.Marshal(msg.Data)
+	}
+
+	return json.Marshal(jmsg)
+}
+
+// UnmarshalJSONMessage reads a JSONMessage back into a Message.
+func UnmarshalJSONMessage(bytes []byte) (Message, error) {
+	jmsg := JSONMessage{}
+	err := json.Unmarshal(bytes, &jmsg)
 	if err != nil {
 		return Message{}, err
 	}
 
-	return Message{headFromBytes(typeBuf), dataBuf}, nil
+	head := Head(jmsg.Head)
+	switch jmsg.Type {
+	case "", ContentJSON.String():
+		return Message{Head: head, Content: ContentJSON, Data: jmsg.Data, Seq: jmsg.Seq}, nil
+	case ContentText.String():
+		var text string
+		err = json.Unmarshal(jmsg.Data, &text)
+		return Message{Head: head, Content: ContentText, Data: []byte(text), Seq: jmsg.Seq}, err
+	case ContentBinary.String():
+		var data []byte
+		err = json.Unmarshal(jmsg.Data, &data)
+		return Message{Head: head, Content: ContentBinary, Data: data, Seq: jmsg.Seq}, err
+	default:
+		return Message{}, fmt.Errorf("unknown content type %s", jmsg.Type)
+	}
 }
 
+// ConnectRequest is the body of the first message from a client. To resume a
+// session, it has the token from the previous ConnectResponse, and the last
+// Seq that was received. Clients that can apply patches to updates say so, to
+// be sent patches instead of whole updates.
 type ConnectRequest struct {
-	Msg string `json:"message"`
+	Msg     string `json:"message"`
+	Resume  string `json:"resume,omitempty"`
+	LastSeq uint32 `json:"lastSeq,omitempty"`
+	Patches bool   `json:"patches,omitempty"`
 }
 
+// ConnectResponse is the reply to a ConnectRequest. Resume is the token for
+// resuming the session later, and Resumed says whether this connection did.
 type ConnectResponse struct {
 	GameID   string      `json:"game"`
 	PlayerID string      `json:"player"`
+	Resume   string      `json:"resume"`
+	Resumed  bool        `json:"resumed"`
 	Err      *CommsError `json:"error"`
 }
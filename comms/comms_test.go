@@ -2,7 +2,9 @@ package comms
 
 import (
 	"bytes"
+	"io"
 	"testing"
+	"testing/iotest"
 )
 
 func TestEncDec(t *testing.T) {
@@ -23,3 +25,200 @@ func TestEncDec(t *testing.T) {
 		t.Errorf("bad decode: %v", msg.Data)
 	}
 }
+
+func TestEncDec_partialReads(t *testing.T) {
+	var network bytes.Buffer
+	enc := NewEncoder(&network)
+
+	err := enc.Encode("test:a", map[string]int{"x": 1})
+	if err != nil {
+		t.Errorf("enc error: %v", err)
+	}
+
+	dec := NewDecoder(iotest.OneByteReader(&network))
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("dec error: %v", err)
+	}
+	if msg.Head != "test:a" {
+		t.Errorf("bad head: %v", msg.Head)
+	}
+	if msg.Content != ContentJSON {
+		t.Errorf("bad content: %v", msg.Content)
+	}
+	if string(msg.Data) != `{"x":1}` {
+		t.Errorf("bad data: %s", msg.Data)
+	}
+}
+
+func TestEncDec_binary(t *testing.T) {
+	var network bytes.Buffer
+	enc := NewEncoder(&network)
+	dec := NewDecoder(&network)
+
+	err := enc.Encode("test", []byte{0, 1, 2})
+	if err != nil {
+		t.Errorf("enc error: %v", err)
+	}
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("dec error: %v", err)
+	}
+	if msg.Content != ContentBinary {
+		t.Errorf("bad content: %v", msg.Content)
+	}
+	if !bytes.Equal(msg.Data, []byte{0, 1, 2}) {
+		t.Errorf("bad data: %v", msg.Data)
+	}
+
+	var v interface{}
+	if err := Decode(msg, &v); err == nil {
+		t.Errorf("decoded binary as json")
+	}
+}
+
+func TestDecode_tooLarge(t *testing.T) {
+	frame := []byte{FrameVersion, byte(ContentJSON), 0, 0, 0, 0, 0, 4, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}
+	dec := NewDecoder(bytes.NewReader(frame))
+
+	_, err := dec.Decode()
+	if err != ErrTooLarge {
+		t.Errorf("expected too large, got: %v", err)
+	}
+}
+
+func TestEncode_tooLarge(t *testing.T) {
+	var network bytes.Buffer
+	enc := NewEncoder(&network)
+	enc.SetMaxSize(8)
+
+	err := enc.Encode("test", "too much data")
+	if err != ErrTooLarge {
+		t.Errorf("expected too large, got: %v", err)
+	}
+	if network.Len() != 0 {
+		t.Errorf("wrote something")
+	}
+}
+
+func TestDecode_badVersion(t *testing.T) {
+	frame := []byte{99, byte(ContentJSON), 0, 0, 0, 0, 0, 4, 0, 0, 0, 0, 't', 'e', 's', 't'}
+	dec := NewDecoder(bytes.NewReader(frame))
+
+	_, err := dec.Decode()
+	if err != ErrBadVersion {
+		t.Errorf("expected bad version, got: %v", err)
+	}
+}
+
+func TestEncDec_seq(t *testing.T) {
+	var network bytes.Buffer
+	enc := NewEncoder(&network)
+	dec := NewDecoder(&network)
+
+	msg, _ := Encode("update", "data")
+	msg.Seq = 42
+	err := enc.Send(msg)
+	if err != nil {
+		t.Errorf("enc error: %v", err)
+	}
+
+	msg1, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("dec error: %v", err)
+	}
+	if msg1.Seq != 42 {
+		t.Errorf("bad seq: %d", msg1.Seq)
+	}
+}
+
+func TestDecode_version1(t *testing.T) {
+	frame := []byte{1, byte(ContentJSON), 0, 0, 0, 0, 0, 4, 0, 0, 0, 2, 't', 'e', 's', 't', '{', '}'}
+	dec := NewDecoder(bytes.NewReader(frame))
+
+	msg, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("dec error: %v", err)
+	}
+	if msg.Head != "test" || string(msg.Data) != "{}" || msg.Seq != 0 {
+		t.Errorf("bad decode: %v", msg)
+	}
+}
+
+func TestDecode_truncated(t *testing.T) {
+	var network bytes.Buffer
+	enc := NewEncoder(&network)
+
+	err := enc.Encode("test", "data")
+	if err != nil {
+		t.Errorf("enc error: %v", err)
+	}
+
+	frame := network.Bytes()
+	for n := 1; n < len(frame); n++ {
+		dec := NewDecoder(bytes.NewReader(frame[:n]))
+		_, err := dec.Decode()
+		if err == nil || err == io.EOF {
+			t.Errorf("no error at %d: %v", n, err)
+		}
+	}
+}
+
+func TestJSONMessage(t *testing.T) {
+	for _, in := range []Message{
+		{Head: "test", Content: ContentJSON, Data: []byte(`{"x":1}`), Seq: 3},
+		{Head: "test", Content: ContentText, Data: []byte("hello")},
+		{Head: "test", Content: ContentBinary, Data: []byte{0, 1, 2}},
+	} {
+		bs, err := MarshalJSONMessage(in)
+		if err != nil {
+			t.Fatalf("marshal error: %v", err)
+		}
+		out, err := UnmarshalJSONMessage(bs)
+		if err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+		if out.Head != in.Head || out.Content != in.Content || out.Seq != in.Seq || !bytes.Equal(out.Data, in.Data) {
+			t.Errorf("bad round trip: %v != %v", out, in)
+		}
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	var network bytes.Buffer
+	enc := NewEncoder(&network)
+	enc.Encode("test", "data")
+	enc.Encode("request:1:play", map[string]string{"command": "dicemove"})
+	f.Add(network.Bytes())
+	f.Add([]byte{FrameVersion, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 1})
+	f.Add([]byte{1, 1, 0, 0, 0, 0, 0, 4, 0, 0, 0, 2, 't', 'e', 's', 't', '{', '}'})
+
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		dec := NewDecoder(bytes.NewReader(frame))
+		dec.SetMaxSize(1024)
+		for {
+			msg, err := dec.Decode()
+			if err != nil {
+				return
+			}
+			if msg.Head.Length()+len(msg.Data) > 1024 {
+				t.Errorf("oversize message decoded")
+			}
+
+			// anything decoded must survive a round trip
+			var out bytes.Buffer
+			err = NewEncoder(&out).Send(msg)
+			if err != nil {
+				t.Fatalf("cannot re-encode: %v", err)
+			}
+			msg1, err := NewDecoder(&out).Decode()
+			if err != nil {
+				t.Fatalf("cannot re-decode: %v", err)
+			}
+			if msg1.Head != msg.Head || msg1.Content != msg.Content || msg1.Seq != msg.Seq || !bytes.Equal(msg1.Data, msg.Data) {
+				t.Errorf("round trip changed message")
+			}
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package comms
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// MergePatch makes a JSON merge patch (RFC 7396) that turns one JSON document
+// into another. Nulls in the new document come out as removals, which is the
+// same thing to anything that decodes into structs.
+func MergePatch(from, to []byte) ([]byte, error) {
+	var a, b interface{}
+	if err := json.Unmarshal(from, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &b); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeDiff(a, b))
+}
+
+func mergeDiff(a, b interface{}) interface{} {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		// anything but an object is replaced whole
+		return b
+	}
+
+	out := map[string]interface{}{}
+	for k := range am {
+		if _, ok := bm[k]; !ok {
+			out[k] = nil
+		}
+	}
+	for k, bv := range bm {
+		av, ok := am[k]
+		if ok && reflect.DeepEqual(av, bv) {
+			continue
+		}
+		if ok {
+			out[k] = mergeDiff(av, bv)
+		} else {
+			out[k] = mergeDiff(nil, bv)
+		}
+	}
+	return out
+}
+
+// ApplyMergePatch applies a JSON merge patch (RFC 7396) to a JSON document.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	var d, p interface{}
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeApply(d, p))
+}
+
+func mergeApply(d, p interface{}) interface{} {
+	pm, ok := p.(map[string]interface{})
+	if !ok {
+		return p
+	}
+	dm, ok := d.(map[string]interface{})
+	if !ok {
+		dm = map[string]interface{}{}
+	}
+	for k, pv := range pm {
+		if pv == nil {
+			delete(dm, k)
+		} else {
+			dm[k] = mergeApply(dm[k], pv)
+		}
+	}
+	return dm
+}
@@ -0,0 +1,58 @@
+package comms
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		from, to, patch string
+	}{
+		{`{"a":1,"b":2}`, `{"a":1,"b":2}`, `{}`},
+		{`{"a":1,"b":2}`, `{"a":1,"b":3}`, `{"b":3}`},
+		{`{"a":1,"b":2}`, `{"a":1}`, `{"b":null}`},
+		{`{"a":{"x":1,"y":[1,2]}}`, `{"a":{"x":1,"y":[1,2,3]}}`, `{"a":{"y":[1,2,3]}}`},
+		{`{"a":{"x":1}}`, `{"a":"x"}`, `{"a":"x"}`},
+		{`{"a":null}`, `{"a":{"x":1}}`, `{"a":{"x":1}}`},
+	}
+
+	for _, test := range tests {
+		patch, err := MergePatch([]byte(test.from), []byte(test.to))
+		if err != nil {
+			t.Fatalf("diff error: %v", err)
+		}
+		if !sameJSON(patch, []byte(test.patch)) {
+			t.Errorf("bad patch from %s to %s: %s", test.from, test.to, patch)
+		}
+
+		doc, err := ApplyMergePatch([]byte(test.from), patch)
+		if err != nil {
+			t.Fatalf("apply error: %v", err)
+		}
+		if !sameJSON(doc, []byte(test.to)) {
+			t.Errorf("bad apply of %s to %s: %s", patch, test.from, doc)
+		}
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// from RFC 7396
+	doc := `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`
+	patch := `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`
+	want := `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`
+
+	out, err := ApplyMergePatch([]byte(doc), []byte(patch))
+	if err != nil || !sameJSON(out, []byte(want)) {
+		t.Errorf("bad apply: %s %v", out, err)
+	}
+}
+
+func sameJSON(a, b []byte) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
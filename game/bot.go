@@ -0,0 +1,53 @@
+package game
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Bot plays a seat in a game. It is given every update for the seat, and
+// returns a command to play, or nil to wait for the next update.
+type Bot interface {
+	Play(update GameUpdate) *Command
+}
+
+// RandomBot plays random commands, from what the turn says can or must be
+// done. It only plays commands that need no arguments filling in.
+type RandomBot struct {
+	rand *rand.Rand
+}
+
+// NewRandomBot makes a RandomBot.
+func NewRandomBot(seed int64) *RandomBot {
+	return &RandomBot{rand.New(rand.NewSource(seed))}
+}
+
+// Play implements Bot.
+func (b *RandomBot) Play(update GameUpdate) *Command {
+	if update.Status != StatusInProgress || update.Turn == nil {
+		return nil
+	}
+
+	// anything that must be done comes first
+	options := concreteCommands(update.Turn.Must)
+	if len(options) == 0 {
+		options = concreteCommands(update.Turn.Can)
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	pick := options[b.rand.Intn(len(options))]
+	return &Command{Command: CommandString(pick)}
+}
+
+// concreteCommands filters out patterns with wildcards in.
+func concreteCommands(patterns []string) []string {
+	var out []string
+	for _, p := range patterns {
+		if !strings.Contains(p, "*") {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestRandomBot_must(t *testing.T) {
+	bot := NewRandomBot(1)
+	update := GameUpdate{
+		Status: StatusInProgress,
+		Turn: &TurnState{
+			Can:  []string{"dicemove", "end"},
+			Must: []string{"pay:*:*", "obeyrisk:3"},
+		},
+	}
+	for i := 0; i < 10; i++ {
+		cmd := bot.Play(update)
+		if cmd == nil || cmd.Command != "obeyrisk:3" {
+			t.Errorf("bad command: %v", cmd)
+		}
+	}
+}
+
+func TestRandomBot_wait(t *testing.T) {
+	bot := NewRandomBot(1)
+	if cmd := bot.Play(GameUpdate{Status: StatusInProgress}); cmd != nil {
+		t.Errorf("played without a turn: %v", cmd)
+	}
+	update := GameUpdate{
+		Status: StatusUnstarted,
+		Turn:   &TurnState{Can: []string{"end"}},
+	}
+	if cmd := bot.Play(update); cmd != nil {
+		t.Errorf("played before start: %v", cmd)
+	}
+}
@@ -2,6 +2,7 @@ package game
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/undeconstructed/gogogo/comms"
 )
@@ -12,10 +13,10 @@ func ReError(cerr *comms.CommsError) error {
 		return nil
 	}
 
-	// XXX - should this check if the code is a known one?
+	// the message may have the code in it already, from GameError.Error()
 	return &GameError{
 		StatusCode(cerr.Code),
-		cerr.Error(),
+		strings.TrimPrefix(cerr.Error(), cerr.Code+": "),
 	}
 }
 
@@ -57,3 +58,12 @@ type GameUpdate struct {
 	// turn object for one player
 	Turn *TurnState `json:"turn"`
 }
+
+// GameUpdatePatch is sent instead of a GameUpdate to clients that ask for
+// patches. Patch is a JSON merge patch (RFC 7396) to the last update, with the
+// seq Base, as JSON without its news. The news is all new, as in an update.
+type GameUpdatePatch struct {
+	Base  uint32          `json:"base"`
+	News  []Change        `json:"news"`
+	Patch json.RawMessage `json:"patch"`
+}
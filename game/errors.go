@@ -1,6 +1,10 @@
 package game
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/undeconstructed/gogogo/comms"
+)
 
 type StatusCode string
 
@@ -10,6 +14,7 @@ type GameError struct {
 }
 
 func (e *GameError) Status() StatusCode { return e.Code }
+func (e *GameError) ErrorCode() string  { return string(e.Code) }
 func (e *GameError) Error() string      { return string(e.Code) + ": " + e.Message }
 
 func Error(code StatusCode, message string) error {
@@ -65,7 +70,7 @@ func StatusMessage(e StatusCode) string {
 
 const (
 	StatusOK      StatusCode = "OK"
-	StatusUnknown StatusCode = "?"
+	StatusUnknown StatusCode = comms.CodeUnknown
 
 	// StatusBadRequest is for requests that would never work in this game
 	StatusBadRequest StatusCode = "BADREQUEST"
@@ -0,0 +1,54 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/comms"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorGRPC_roundTrip(t *testing.T) {
+	for _, code := range []StatusCode{StatusBadRequest, StatusConflict, StatusNoPlayers, StatusAlreadyStarted,
+		StatusNotStarted, StatusWrongPhase, StatusMustDo, StatusNotYourTurn, StatusNotNow} {
+		err := ErrorFromGRPC(ErrorToGRPC(Error(code, "oops")))
+		ge, ok := err.(*GameError)
+		if !ok || ge.Code != code || ge.Message != "oops" {
+			t.Errorf("bad round trip of %s: %#v", code, err)
+		}
+	}
+
+	if c := status.Code(ErrorToGRPC(Error(StatusBadRequest, ""))); c != codes.InvalidArgument {
+		t.Errorf("bad request should be invalid argument, got %v", c)
+	}
+	if c := status.Code(ErrorToGRPC(Error(StatusNotYourTurn, ""))); c != codes.FailedPrecondition {
+		t.Errorf("not your turn should be failed precondition, got %v", c)
+	}
+
+	// other errors are unknown
+	if c := Code(ErrorFromGRPC(ErrorToGRPC(errors.New("oops")))); c != StatusUnknown {
+		t.Errorf("bad code: %v", c)
+	}
+	// and gRPC errors without details are left alone
+	plain := status.Error(codes.Unavailable, "gone")
+	if err := ErrorToGRPC(plain); err != plain {
+		t.Errorf("plain error changed: %v", err)
+	}
+	if err := ErrorFromGRPC(plain); err != plain {
+		t.Errorf("plain error changed: %v", err)
+	}
+}
+
+func TestReError(t *testing.T) {
+	err := ReError(comms.WrapError(Error(StatusNotNow, "not now")))
+	if Code(err) != StatusNotNow || err.Error() != "NOTNOW: not now" {
+		t.Errorf("bad error: %v", err)
+	}
+
+	err = ReError(comms.WrapError(errors.New("oops")))
+	if Code(err) != StatusUnknown || err.(*GameError).Message != "oops" {
+		t.Errorf("bad error: %v", err)
+	}
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.26.0
+// 	protoc-gen-go v1.27.1
 // 	protoc        v3.12.4
 // source: game/game.proto
 
@@ -58,6 +58,55 @@ func (*Empty) Descriptor() ([]byte, []int) {
 	return file_game_game_proto_rawDescGZIP(), []int{0}
 }
 
+// RErrorDetail goes in the details of gRPC errors, to say which game error it
+// was, e.g. NOTYOURTURN.
+type RErrorDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+}
+
+func (x *RErrorDetail) Reset() {
+	*x = RErrorDetail{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RErrorDetail) ProtoMessage() {}
+
+func (x *RErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RErrorDetail.ProtoReflect.Descriptor instead.
+func (*RErrorDetail) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RErrorDetail) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
 // RGameState is the whole state of the game.
 type RGameState struct {
 	state         protoimpl.MessageState
@@ -81,7 +130,7 @@ type RGameState struct {
 func (x *RGameState) Reset() {
 	*x = RGameState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[1]
+		mi := &file_game_game_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -94,7 +143,7 @@ func (x *RGameState) String() string {
 func (*RGameState) ProtoMessage() {}
 
 func (x *RGameState) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[1]
+	mi := &file_game_game_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,7 +156,7 @@ func (x *RGameState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RGameState.ProtoReflect.Descriptor instead.
 func (*RGameState) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{1}
+	return file_game_game_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *RGameState) GetStatus() string {
@@ -168,7 +217,7 @@ type RPlayerState struct {
 func (x *RPlayerState) Reset() {
 	*x = RPlayerState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[2]
+		mi := &file_game_game_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -181,7 +230,7 @@ func (x *RPlayerState) String() string {
 func (*RPlayerState) ProtoMessage() {}
 
 func (x *RPlayerState) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[2]
+	mi := &file_game_game_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,7 +243,7 @@ func (x *RPlayerState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RPlayerState.ProtoReflect.Descriptor instead.
 func (*RPlayerState) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{2}
+	return file_game_game_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *RPlayerState) GetName() string {
@@ -237,7 +286,7 @@ type RTurnState struct {
 func (x *RTurnState) Reset() {
 	*x = RTurnState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[3]
+		mi := &file_game_game_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -250,7 +299,7 @@ func (x *RTurnState) String() string {
 func (*RTurnState) ProtoMessage() {}
 
 func (x *RTurnState) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[3]
+	mi := &file_game_game_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -263,7 +312,7 @@ func (x *RTurnState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RTurnState.ProtoReflect.Descriptor instead.
 func (*RTurnState) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{3}
+	return file_game_game_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *RTurnState) GetNumber() int32 {
@@ -303,12 +352,15 @@ type RChange struct {
 	Who   string `protobuf:"bytes,1,opt,name=who,proto3" json:"who,omitempty"`
 	What  string `protobuf:"bytes,2,opt,name=what,proto3" json:"what,omitempty"`
 	Where string `protobuf:"bytes,3,opt,name=where,proto3" json:"where,omitempty"`
+	Code  string `protobuf:"bytes,4,opt,name=code,proto3" json:"code,omitempty"`
+	// JSON object of params
+	Params []byte `protobuf:"bytes,5,opt,name=params,proto3" json:"params,omitempty"`
 }
 
 func (x *RChange) Reset() {
 	*x = RChange{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[4]
+		mi := &file_game_game_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -321,7 +373,7 @@ func (x *RChange) String() string {
 func (*RChange) ProtoMessage() {}
 
 func (x *RChange) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[4]
+	mi := &file_game_game_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +386,7 @@ func (x *RChange) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RChange.ProtoReflect.Descriptor instead.
 func (*RChange) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{4}
+	return file_game_game_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RChange) GetWho() string {
@@ -358,6 +410,20 @@ func (x *RChange) GetWhere() string {
 	return ""
 }
 
+func (x *RChange) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RChange) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
 type RLoadRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -369,7 +435,7 @@ type RLoadRequest struct {
 func (x *RLoadRequest) Reset() {
 	*x = RLoadRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[5]
+		mi := &file_game_game_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -382,7 +448,7 @@ func (x *RLoadRequest) String() string {
 func (*RLoadRequest) ProtoMessage() {}
 
 func (x *RLoadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[5]
+	mi := &file_game_game_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -395,7 +461,7 @@ func (x *RLoadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RLoadRequest.ProtoReflect.Descriptor instead.
 func (*RLoadRequest) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{5}
+	return file_game_game_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RLoadRequest) GetId() string {
@@ -416,7 +482,7 @@ type RLoadResponse struct {
 func (x *RLoadResponse) Reset() {
 	*x = RLoadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[6]
+		mi := &file_game_game_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -429,7 +495,7 @@ func (x *RLoadResponse) String() string {
 func (*RLoadResponse) ProtoMessage() {}
 
 func (x *RLoadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[6]
+	mi := &file_game_game_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -442,7 +508,7 @@ func (x *RLoadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RLoadResponse.ProtoReflect.Descriptor instead.
 func (*RLoadResponse) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{6}
+	return file_game_game_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *RLoadResponse) GetState() *RGameState {
@@ -465,7 +531,7 @@ type RInitRequest struct {
 func (x *RInitRequest) Reset() {
 	*x = RInitRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[7]
+		mi := &file_game_game_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -478,7 +544,7 @@ func (x *RInitRequest) String() string {
 func (*RInitRequest) ProtoMessage() {}
 
 func (x *RInitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[7]
+	mi := &file_game_game_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -491,7 +557,7 @@ func (x *RInitRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RInitRequest.ProtoReflect.Descriptor instead.
 func (*RInitRequest) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{7}
+	return file_game_game_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *RInitRequest) GetId() string {
@@ -519,7 +585,7 @@ type RInitResponse struct {
 func (x *RInitResponse) Reset() {
 	*x = RInitResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[8]
+		mi := &file_game_game_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -532,7 +598,7 @@ func (x *RInitResponse) String() string {
 func (*RInitResponse) ProtoMessage() {}
 
 func (x *RInitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[8]
+	mi := &file_game_game_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -545,7 +611,7 @@ func (x *RInitResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RInitResponse.ProtoReflect.Descriptor instead.
 func (*RInitResponse) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{8}
+	return file_game_game_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *RInitResponse) GetState() *RGameState {
@@ -560,6 +626,8 @@ type RAddPlayerRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// game id, as given to Init or Load
+	Game string `protobuf:"bytes,2,opt,name=game,proto3" json:"game,omitempty"`
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// custom JSON options
 	Options []byte `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
@@ -568,7 +636,7 @@ type RAddPlayerRequest struct {
 func (x *RAddPlayerRequest) Reset() {
 	*x = RAddPlayerRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[9]
+		mi := &file_game_game_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -581,7 +649,7 @@ func (x *RAddPlayerRequest) String() string {
 func (*RAddPlayerRequest) ProtoMessage() {}
 
 func (x *RAddPlayerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[9]
+	mi := &file_game_game_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -594,7 +662,14 @@ func (x *RAddPlayerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RAddPlayerRequest.ProtoReflect.Descriptor instead.
 func (*RAddPlayerRequest) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{9}
+	return file_game_game_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RAddPlayerRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
 }
 
 func (x *RAddPlayerRequest) GetName() string {
@@ -622,7 +697,7 @@ type RAddPlayerResponse struct {
 func (x *RAddPlayerResponse) Reset() {
 	*x = RAddPlayerResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[10]
+		mi := &file_game_game_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -635,7 +710,7 @@ func (x *RAddPlayerResponse) String() string {
 func (*RAddPlayerResponse) ProtoMessage() {}
 
 func (x *RAddPlayerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[10]
+	mi := &file_game_game_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -648,7 +723,7 @@ func (x *RAddPlayerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RAddPlayerResponse.ProtoReflect.Descriptor instead.
 func (*RAddPlayerResponse) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{10}
+	return file_game_game_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RAddPlayerResponse) GetState() *RGameState {
@@ -662,12 +737,14 @@ type RStartRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Game string `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
 }
 
 func (x *RStartRequest) Reset() {
 	*x = RStartRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[11]
+		mi := &file_game_game_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -680,7 +757,7 @@ func (x *RStartRequest) String() string {
 func (*RStartRequest) ProtoMessage() {}
 
 func (x *RStartRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[11]
+	mi := &file_game_game_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -693,7 +770,14 @@ func (x *RStartRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RStartRequest.ProtoReflect.Descriptor instead.
 func (*RStartRequest) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{11}
+	return file_game_game_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RStartRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
 }
 
 type RStartResponse struct {
@@ -707,7 +791,7 @@ type RStartResponse struct {
 func (x *RStartResponse) Reset() {
 	*x = RStartResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[12]
+		mi := &file_game_game_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -720,7 +804,7 @@ func (x *RStartResponse) String() string {
 func (*RStartResponse) ProtoMessage() {}
 
 func (x *RStartResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[12]
+	mi := &file_game_game_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -733,7 +817,7 @@ func (x *RStartResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RStartResponse.ProtoReflect.Descriptor instead.
 func (*RStartResponse) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{12}
+	return file_game_game_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RStartResponse) GetState() *RGameState {
@@ -749,6 +833,7 @@ type RPlayRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	Game string `protobuf:"bytes,4,opt,name=game,proto3" json:"game,omitempty"`
 	// player ID, must be server set.
 	Player string `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
 	// command, is in CommandString format
@@ -760,7 +845,7 @@ type RPlayRequest struct {
 func (x *RPlayRequest) Reset() {
 	*x = RPlayRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[13]
+		mi := &file_game_game_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -773,7 +858,7 @@ func (x *RPlayRequest) String() string {
 func (*RPlayRequest) ProtoMessage() {}
 
 func (x *RPlayRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[13]
+	mi := &file_game_game_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -786,7 +871,14 @@ func (x *RPlayRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RPlayRequest.ProtoReflect.Descriptor instead.
 func (*RPlayRequest) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{13}
+	return file_game_game_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RPlayRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
 }
 
 func (x *RPlayRequest) GetPlayer() string {
@@ -827,7 +919,7 @@ type RPlayResponse struct {
 func (x *RPlayResponse) Reset() {
 	*x = RPlayResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[14]
+		mi := &file_game_game_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -840,7 +932,7 @@ func (x *RPlayResponse) String() string {
 func (*RPlayResponse) ProtoMessage() {}
 
 func (x *RPlayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[14]
+	mi := &file_game_game_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -853,7 +945,7 @@ func (x *RPlayResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RPlayResponse.ProtoReflect.Descriptor instead.
 func (*RPlayResponse) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{14}
+	return file_game_game_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *RPlayResponse) GetResponse() []byte {
@@ -877,18 +969,19 @@ func (x *RPlayResponse) GetState() *RGameState {
 	return nil
 }
 
-// RDestroyRequest takes out the game instance entirely, and also shuts down
-// the process.
+// RDestroyRequest takes out the game instance entirely, including its save.
 type RDestroyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Game string `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
 }
 
 func (x *RDestroyRequest) Reset() {
 	*x = RDestroyRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[15]
+		mi := &file_game_game_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -901,7 +994,7 @@ func (x *RDestroyRequest) String() string {
 func (*RDestroyRequest) ProtoMessage() {}
 
 func (x *RDestroyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[15]
+	mi := &file_game_game_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -914,7 +1007,14 @@ func (x *RDestroyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RDestroyRequest.ProtoReflect.Descriptor instead.
 func (*RDestroyRequest) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{15}
+	return file_game_game_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RDestroyRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
 }
 
 type RDestroyResponse struct {
@@ -926,7 +1026,7 @@ type RDestroyResponse struct {
 func (x *RDestroyResponse) Reset() {
 	*x = RDestroyResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_game_game_proto_msgTypes[16]
+		mi := &file_game_game_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -939,7 +1039,7 @@ func (x *RDestroyResponse) String() string {
 func (*RDestroyResponse) ProtoMessage() {}
 
 func (x *RDestroyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_game_game_proto_msgTypes[16]
+	mi := &file_game_game_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -952,191 +1052,826 @@ func (x *RDestroyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RDestroyResponse.ProtoReflect.Descriptor instead.
 func (*RDestroyResponse) Descriptor() ([]byte, []int) {
-	return file_game_game_proto_rawDescGZIP(), []int{16}
+	return file_game_game_proto_rawDescGZIP(), []int{17}
 }
 
-var File_game_game_proto protoreflect.FileDescriptor
+// RUnloadRequest drops a game from the plugin, leaving its save to be loaded
+// again.
+type RUnloadRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_game_game_proto_rawDesc = []byte{
-	0x0a, 0x0f, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x22, 0xbc, 0x01, 0x0a, 0x0a, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
-	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69,
-	0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e,
-	0x67, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x75, 0x72,
-	0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74,
-	0x75, 0x72, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x6c, 0x6f,
-	0x62, 0x61, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x67, 0x6c, 0x6f, 0x62, 0x61,
-	0x6c, 0x12, 0x2c, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x65,
-	0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x22,
-	0x62, 0x0a, 0x0c, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x54, 0x75, 0x72, 0x6e, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x52, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69,
-	0x76, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x72, 0x69, 0x76,
-	0x61, 0x74, 0x65, 0x22, 0x62, 0x0a, 0x0a, 0x52, 0x54, 0x75, 0x72, 0x6e, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x61, 0x6e,
-	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x63, 0x61, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6d,
-	0x75, 0x73, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x75, 0x73, 0x74, 0x12,
-	0x16, 0x0a, 0x06, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x06, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x22, 0x45, 0x0a, 0x07, 0x52, 0x43, 0x68, 0x61, 0x6e,
-	0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x77, 0x68, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x77, 0x68, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x68, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x77, 0x68, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x68, 0x65, 0x72,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x77, 0x68, 0x65, 0x72, 0x65, 0x22, 0x1e,
-	0x0a, 0x0c, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x37,
-	0x0a, 0x0d, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
-	0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x38, 0x0a, 0x0c, 0x52, 0x49, 0x6e, 0x69, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x22, 0x37, 0x0a, 0x0d, 0x52, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x41, 0x0a, 0x11, 0x52, 0x41,
-	0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x3c, 0x0a,
-	0x12, 0x52, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x0f, 0x0a, 0x0d, 0x52,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x38, 0x0a, 0x0e,
-	0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26,
-	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
-	0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
-	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x5a, 0x0a, 0x0c, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x18,
-	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x22, 0x76, 0x0a, 0x0d, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x21, 0x0a, 0x04, 0x6e, 0x65, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
-	0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x6e, 0x65,
-	0x77, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x52, 0x44,
-	0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x12, 0x0a,
-	0x10, 0x52, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x32, 0xcb, 0x02, 0x0a, 0x08, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x2f,
-	0x0a, 0x04, 0x4c, 0x6f, 0x61, 0x64, 0x12, 0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x4c,
-	0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x61, 0x6d,
-	0x65, 0x2e, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x2f, 0x0a, 0x04, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52,
-	0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x61,
-	0x6d, 0x65, 0x2e, 0x52, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x3e, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x17, 0x2e,
-	0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x41,
-	0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x32, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x13, 0x2e, 0x67, 0x61, 0x6d, 0x65,
-	0x2e, 0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
-	0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x50, 0x6c, 0x61, 0x79, 0x12, 0x12, 0x2e, 0x67,
-	0x61, 0x6d, 0x65, 0x2e, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x13, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x07, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
-	0x12, 0x15, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52,
-	0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42,
-	0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x6e,
-	0x64, 0x65, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f,
-	0x67, 0x6f, 0x67, 0x6f, 0x2f, 0x67, 0x61, 0x6d, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	Game string `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
 }
 
-var (
-	file_game_game_proto_rawDescOnce sync.Once
-	file_game_game_proto_rawDescData = file_game_game_proto_rawDesc
-)
+func (x *RUnloadRequest) Reset() {
+	*x = RUnloadRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_game_game_proto_rawDescGZIP() []byte {
-	file_game_game_proto_rawDescOnce.Do(func() {
-		file_game_game_proto_rawDescData = protoimpl.X.CompressGZIP(file_game_game_proto_rawDescData)
-	})
-	return file_game_game_proto_rawDescData
+func (x *RUnloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_game_game_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
-var file_game_game_proto_goTypes = []interface{}{
-	(*Empty)(nil),              // 0: game.Empty
-	(*RGameState)(nil),         // 1: game.RGameState
-	(*RPlayerState)(nil),       // 2: game.RPlayerState
-	(*RTurnState)(nil),         // 3: game.RTurnState
-	(*RChange)(nil),            // 4: game.RChange
-	(*RLoadRequest)(nil),       // 5: game.RLoadRequest
-	(*RLoadResponse)(nil),      // 6: game.RLoadResponse
-	(*RInitRequest)(nil),       // 7: game.RInitRequest
-	(*RInitResponse)(nil),      // 8: game.RInitResponse
-	(*RAddPlayerRequest)(nil),  // 9: game.RAddPlayerRequest
-	(*RAddPlayerResponse)(nil), // 10: game.RAddPlayerResponse
-	(*RStartRequest)(nil),      // 11: game.RStartRequest
-	(*RStartResponse)(nil),     // 12: game.RStartResponse
-	(*RPlayRequest)(nil),       // 13: game.RPlayRequest
-	(*RPlayResponse)(nil),      // 14: game.RPlayResponse
-	(*RDestroyRequest)(nil),    // 15: game.RDestroyRequest
-	(*RDestroyResponse)(nil),   // 16: game.RDestroyResponse
+func (*RUnloadRequest) ProtoMessage() {}
+
+func (x *RUnloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_game_game_proto_depIdxs = []int32{
-	2,  // 0: game.RGameState.players:type_name -> game.RPlayerState
-	3,  // 1: game.RPlayerState.turn:type_name -> game.RTurnState
-	1,  // 2: game.RLoadResponse.state:type_name -> game.RGameState
-	1,  // 3: game.RInitResponse.state:type_name -> game.RGameState
-	1,  // 4: game.RAddPlayerResponse.state:type_name -> game.RGameState
-	1,  // 5: game.RStartResponse.state:type_name -> game.RGameState
-	4,  // 6: game.RPlayResponse.news:type_name -> game.RChange
-	1,  // 7: game.RPlayResponse.state:type_name -> game.RGameState
-	5,  // 8: game.Instance.Load:input_type -> game.RLoadRequest
-	7,  // 9: game.Instance.Init:input_type -> game.RInitRequest
-	9,  // 10: game.Instance.AddPlayer:input_type -> game.RAddPlayerRequest
-	11, // 11: game.Instance.Start:input_type -> game.RStartRequest
-	13, // 12: game.Instance.Play:input_type -> game.RPlayRequest
-	15, // 13: game.Instance.Destroy:input_type -> game.RDestroyRequest
-	6,  // 14: game.Instance.Load:output_type -> game.RLoadResponse
-	8,  // 15: game.Instance.Init:output_type -> game.RInitResponse
-	10, // 16: game.Instance.AddPlayer:output_type -> game.RAddPlayerResponse
-	12, // 17: game.Instance.Start:output_type -> game.RStartResponse
-	14, // 18: game.Instance.Play:output_type -> game.RPlayResponse
-	16, // 19: game.Instance.Destroy:output_type -> game.RDestroyResponse
-	14, // [14:20] is the sub-list for method output_type
-	8,  // [8:14] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+
+// Deprecated: Use RUnloadRequest.ProtoReflect.Descriptor instead.
+func (*RUnloadRequest) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{18}
 }
 
-func init() { file_game_game_proto_init() }
-func file_game_game_proto_init() {
-	if File_game_game_proto != nil {
-		return
+func (x *RUnloadRequest) GetGame() string {
+	if x != nil {
+		return x.Game
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_game_game_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Empty); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return ""
+}
+
+type RUnloadResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RUnloadResponse) Reset() {
+	*x = RUnloadResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RUnloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RUnloadResponse) ProtoMessage() {}
+
+func (x *RUnloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_game_game_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RGameState); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RUnloadResponse.ProtoReflect.Descriptor instead.
+func (*RUnloadResponse) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{19}
+}
+
+// RBotRequest asks one of the plugin's bots what to play in a seat.
+type RBotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game   string `protobuf:"bytes,4,opt,name=game,proto3" json:"game,omitempty"`
+	Player string `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	// kind of bot, one of those in the description
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// JSON GameUpdate, as sent to a person in the seat
+	Update []byte `protobuf:"bytes,3,opt,name=update,proto3" json:"update,omitempty"`
+}
+
+func (x *RBotRequest) Reset() {
+	*x = RBotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RBotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RBotRequest) ProtoMessage() {}
+
+func (x *RBotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RBotRequest.ProtoReflect.Descriptor instead.
+func (*RBotRequest) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RBotRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *RBotRequest) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *RBotRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *RBotRequest) GetUpdate() []byte {
+	if x != nil {
+		return x.Update
+	}
+	return nil
+}
+
+// RBotResponse is what the bot wants to play, if anything.
+type RBotResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// command, in CommandString format, or empty to wait for the next update
+	Command string `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Options string `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *RBotResponse) Reset() {
+	*x = RBotResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RBotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RBotResponse) ProtoMessage() {}
+
+func (x *RBotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RBotResponse.ProtoReflect.Descriptor instead.
+func (*RBotResponse) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RBotResponse) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *RBotResponse) GetOptions() string {
+	if x != nil {
+		return x.Options
+	}
+	return ""
+}
+
+type RDescribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RDescribeRequest) Reset() {
+	*x = RDescribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RDescribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RDescribeRequest) ProtoMessage() {}
+
+func (x *RDescribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RDescribeRequest.ProtoReflect.Descriptor instead.
+func (*RDescribeRequest) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{22}
+}
+
+// RDescribeResponse says what a game type is, and what options it takes.
+type RDescribeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type name, as given to the server
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// name to show people
+	DisplayName string `protobuf:"bytes,2,opt,name=displayName,proto3" json:"displayName,omitempty"`
+	MinPlayers  int32  `protobuf:"varint,3,opt,name=minPlayers,proto3" json:"minPlayers,omitempty"`
+	MaxPlayers  int32  `protobuf:"varint,4,opt,name=maxPlayers,proto3" json:"maxPlayers,omitempty"`
+	// JSON Schema for the game options
+	Options []byte `protobuf:"bytes,5,opt,name=options,proto3" json:"options,omitempty"`
+	// JSON Schema for each player's options
+	PlayerOptions []byte `protobuf:"bytes,6,opt,name=playerOptions,proto3" json:"playerOptions,omitempty"`
+	// kinds of bot that can play seats, e.g. hard
+	Bots []string `protobuf:"bytes,7,rep,name=bots,proto3" json:"bots,omitempty"`
+	// version of the game's save format, so saves can be moved between servers
+	Version string `protobuf:"bytes,8,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *RDescribeResponse) Reset() {
+	*x = RDescribeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RDescribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RDescribeResponse) ProtoMessage() {}
+
+func (x *RDescribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RDescribeResponse.ProtoReflect.Descriptor instead.
+func (*RDescribeResponse) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RDescribeResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RDescribeResponse) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *RDescribeResponse) GetMinPlayers() int32 {
+	if x != nil {
+		return x.MinPlayers
+	}
+	return 0
+}
+
+func (x *RDescribeResponse) GetMaxPlayers() int32 {
+	if x != nil {
+		return x.MaxPlayers
+	}
+	return 0
+}
+
+func (x *RDescribeResponse) GetOptions() []byte {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *RDescribeResponse) GetPlayerOptions() []byte {
+	if x != nil {
+		return x.PlayerOptions
+	}
+	return nil
+}
+
+func (x *RDescribeResponse) GetBots() []string {
+	if x != nil {
+		return x.Bots
+	}
+	return nil
+}
+
+func (x *RDescribeResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type RListAssetsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RListAssetsRequest) Reset() {
+	*x = RListAssetsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RListAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RListAssetsRequest) ProtoMessage() {}
+
+func (x *RListAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RListAssetsRequest.ProtoReflect.Descriptor instead.
+func (*RListAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{24}
+}
+
+type RListAssetsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// paths of every asset, e.g. web/index.html
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+}
+
+func (x *RListAssetsResponse) Reset() {
+	*x = RListAssetsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RListAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RListAssetsResponse) ProtoMessage() {}
+
+func (x *RListAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RListAssetsResponse.ProtoReflect.Descriptor instead.
+func (*RListAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RListAssetsResponse) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+type RGetAssetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *RGetAssetRequest) Reset() {
+	*x = RGetAssetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RGetAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RGetAssetRequest) ProtoMessage() {}
+
+func (x *RGetAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RGetAssetRequest.ProtoReflect.Descriptor instead.
+func (*RGetAssetRequest) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RGetAssetRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RGetAssetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *RGetAssetResponse) Reset() {
+	*x = RGetAssetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_game_game_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RGetAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RGetAssetResponse) ProtoMessage() {}
+
+func (x *RGetAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_game_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RGetAssetResponse.ProtoReflect.Descriptor instead.
+func (*RGetAssetResponse) Descriptor() ([]byte, []int) {
+	return file_game_game_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RGetAssetResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_game_game_proto protoreflect.FileDescriptor
+
+var file_game_game_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x67, 0x61, 0x6d, 0x65, 0x2f, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x22, 0x0a, 0x0c, 0x52, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x0a, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6c,
+	0x61, 0x79, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x1e, 0x0a,
+	0x0a, 0x74, 0x75, 0x72, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0a, 0x74, 0x75, 0x72, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a,
+	0x06, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x67,
+	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x12, 0x2c, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x50,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x22, 0x62, 0x0a, 0x0c, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x54, 0x75,
+	0x72, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x04, 0x74, 0x75, 0x72, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x22, 0x62, 0x0a, 0x0a, 0x52, 0x54, 0x75, 0x72, 0x6e,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x10, 0x0a,
+	0x03, 0x63, 0x61, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x63, 0x61, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6d, 0x75, 0x73, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6d,
+	0x75, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x22, 0x71, 0x0a, 0x07, 0x52,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x77, 0x68, 0x6f, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x77, 0x68, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x77, 0x68, 0x61, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x77, 0x68, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x77, 0x68, 0x65, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x77, 0x68, 0x65,
+	0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x1e,
+	0x0a, 0x0c, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x37,
+	0x0a, 0x0d, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x38, 0x0a, 0x0c, 0x52, 0x49, 0x6e, 0x69, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x37, 0x0a, 0x0d, 0x52, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x55, 0x0a, 0x11, 0x52, 0x41,
+	0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x3c, 0x0a, 0x12, 0x52, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47,
+	0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22,
+	0x23, 0x0a, 0x0d, 0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x67, 0x61, 0x6d, 0x65, 0x22, 0x38, 0x0a, 0x0e, 0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x6e,
+	0x0a, 0x0c, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x76,
+	0x0a, 0x0d, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x6e,
+	0x65, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x52, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x6e, 0x65, 0x77, 0x73, 0x12, 0x26,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x25, 0x0a, 0x0f, 0x52, 0x44, 0x65, 0x73, 0x74, 0x72,
+	0x6f, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x22, 0x12, 0x0a,
+	0x10, 0x52, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x24, 0x0a, 0x0e, 0x52, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x22, 0x11, 0x0a, 0x0f, 0x52, 0x55, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x65, 0x0a, 0x0b, 0x52, 0x42,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x22, 0x42, 0x0a, 0x0c, 0x52, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x52, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xf7, 0x01, 0x0a, 0x11, 0x52, 0x44,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61,
+	0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x50, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x69, 0x6e, 0x50, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x50, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x50, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x24, 0x0a, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0x14, 0x0a, 0x12, 0x52, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2b, 0x0a, 0x13, 0x52, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0x26, 0x0a, 0x10, 0x52, 0x47, 0x65, 0x74, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x27,
+	0x0a, 0x11, 0x52, 0x47, 0x65, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x32, 0xed, 0x04, 0x0a, 0x08, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x12, 0x16, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e,
+	0x52, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x41, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12,
+	0x18, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x52, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x12, 0x16, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x47, 0x65, 0x74, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e,
+	0x52, 0x47, 0x65, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2f, 0x0a, 0x04, 0x4c, 0x6f, 0x61, 0x64, 0x12, 0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x4c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x12, 0x2e, 0x67, 0x61, 0x6d,
+	0x65, 0x2e, 0x52, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x17, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x67, 0x61, 0x6d, 0x65,
+	0x2e, 0x52, 0x41, 0x64, 0x64, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x13, 0x2e, 0x67,
+	0x61, 0x6d, 0x65, 0x2e, 0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x14, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x50, 0x6c, 0x61, 0x79, 0x12,
+	0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x50, 0x6c, 0x61, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x03, 0x42, 0x6f, 0x74, 0x12,
+	0x11, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x42, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x12, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x42, 0x6f, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x07, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f,
+	0x79, 0x12, 0x15, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e,
+	0x52, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x35, 0x0a, 0x06, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x14, 0x2e, 0x67, 0x61, 0x6d,
+	0x65, 0x2e, 0x52, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x15, 0x2e, 0x67, 0x61, 0x6d, 0x65, 0x2e, 0x52, 0x55, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x6e, 0x64, 0x65, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x75, 0x63, 0x74, 0x65, 0x64, 0x2f, 0x67, 0x6f, 0x67, 0x6f, 0x67, 0x6f, 0x2f, 0x67, 0x61, 0x6d,
+	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_game_game_proto_rawDescOnce sync.Once
+	file_game_game_proto_rawDescData = file_game_game_proto_rawDesc
+)
+
+func file_game_game_proto_rawDescGZIP() []byte {
+	file_game_game_proto_rawDescOnce.Do(func() {
+		file_game_game_proto_rawDescData = protoimpl.X.CompressGZIP(file_game_game_proto_rawDescData)
+	})
+	return file_game_game_proto_rawDescData
+}
+
+var file_game_game_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_game_game_proto_goTypes = []interface{}{
+	(*Empty)(nil),               // 0: game.Empty
+	(*RErrorDetail)(nil),        // 1: game.RErrorDetail
+	(*RGameState)(nil),          // 2: game.RGameState
+	(*RPlayerState)(nil),        // 3: game.RPlayerState
+	(*RTurnState)(nil),          // 4: game.RTurnState
+	(*RChange)(nil),             // 5: game.RChange
+	(*RLoadRequest)(nil),        // 6: game.RLoadRequest
+	(*RLoadResponse)(nil),       // 7: game.RLoadResponse
+	(*RInitRequest)(nil),        // 8: game.RInitRequest
+	(*RInitResponse)(nil),       // 9: game.RInitResponse
+	(*RAddPlayerRequest)(nil),   // 10: game.RAddPlayerRequest
+	(*RAddPlayerResponse)(nil),  // 11: game.RAddPlayerResponse
+	(*RStartRequest)(nil),       // 12: game.RStartRequest
+	(*RStartResponse)(nil),      // 13: game.RStartResponse
+	(*RPlayRequest)(nil),        // 14: game.RPlayRequest
+	(*RPlayResponse)(nil),       // 15: game.RPlayResponse
+	(*RDestroyRequest)(nil),     // 16: game.RDestroyRequest
+	(*RDestroyResponse)(nil),    // 17: game.RDestroyResponse
+	(*RUnloadRequest)(nil),      // 18: game.RUnloadRequest
+	(*RUnloadResponse)(nil),     // 19: game.RUnloadResponse
+	(*RBotRequest)(nil),         // 20: game.RBotRequest
+	(*RBotResponse)(nil),        // 21: game.RBotResponse
+	(*RDescribeRequest)(nil),    // 22: game.RDescribeRequest
+	(*RDescribeResponse)(nil),   // 23: game.RDescribeResponse
+	(*RListAssetsRequest)(nil),  // 24: game.RListAssetsRequest
+	(*RListAssetsResponse)(nil), // 25: game.RListAssetsResponse
+	(*RGetAssetRequest)(nil),    // 26: game.RGetAssetRequest
+	(*RGetAssetResponse)(nil),   // 27: game.RGetAssetResponse
+}
+var file_game_game_proto_depIdxs = []int32{
+	3,  // 0: game.RGameState.players:type_name -> game.RPlayerState
+	4,  // 1: game.RPlayerState.turn:type_name -> game.RTurnState
+	2,  // 2: game.RLoadResponse.state:type_name -> game.RGameState
+	2,  // 3: game.RInitResponse.state:type_name -> game.RGameState
+	2,  // 4: game.RAddPlayerResponse.state:type_name -> game.RGameState
+	2,  // 5: game.RStartResponse.state:type_name -> game.RGameState
+	5,  // 6: game.RPlayResponse.news:type_name -> game.RChange
+	2,  // 7: game.RPlayResponse.state:type_name -> game.RGameState
+	22, // 8: game.Instance.Describe:input_type -> game.RDescribeRequest
+	24, // 9: game.Instance.ListAssets:input_type -> game.RListAssetsRequest
+	26, // 10: game.Instance.GetAsset:input_type -> game.RGetAssetRequest
+	6,  // 11: game.Instance.Load:input_type -> game.RLoadRequest
+	8,  // 12: game.Instance.Init:input_type -> game.RInitRequest
+	10, // 13: game.Instance.AddPlayer:input_type -> game.RAddPlayerRequest
+	12, // 14: game.Instance.Start:input_type -> game.RStartRequest
+	14, // 15: game.Instance.Play:input_type -> game.RPlayRequest
+	20, // 16: game.Instance.Bot:input_type -> game.RBotRequest
+	16, // 17: game.Instance.Destroy:input_type -> game.RDestroyRequest
+	18, // 18: game.Instance.Unload:input_type -> game.RUnloadRequest
+	23, // 19: game.Instance.Describe:output_type -> game.RDescribeResponse
+	25, // 20: game.Instance.ListAssets:output_type -> game.RListAssetsResponse
+	27, // 21: game.Instance.GetAsset:output_type -> game.RGetAssetResponse
+	7,  // 22: game.Instance.Load:output_type -> game.RLoadResponse
+	9,  // 23: game.Instance.Init:output_type -> game.RInitResponse
+	11, // 24: game.Instance.AddPlayer:output_type -> game.RAddPlayerResponse
+	13, // 25: game.Instance.Start:output_type -> game.RStartResponse
+	15, // 26: game.Instance.Play:output_type -> game.RPlayResponse
+	21, // 27: game.Instance.Bot:output_type -> game.RBotResponse
+	17, // 28: game.Instance.Destroy:output_type -> game.RDestroyResponse
+	19, // 29: game.Instance.Unload:output_type -> game.RUnloadResponse
+	19, // [19:30] is the sub-list for method output_type
+	8,  // [8:19] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_game_game_proto_init() }
+func file_game_game_proto_init() {
+	if File_game_game_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_game_game_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RErrorDetail); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1148,7 +1883,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RPlayerState); i {
+			switch v := v.(*RGameState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1160,7 +1895,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RTurnState); i {
+			switch v := v.(*RPlayerState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1172,7 +1907,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RChange); i {
+			switch v := v.(*RTurnState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1184,7 +1919,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RLoadRequest); i {
+			switch v := v.(*RChange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1196,7 +1931,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RLoadResponse); i {
+			switch v := v.(*RLoadRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1208,7 +1943,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RInitRequest); i {
+			switch v := v.(*RLoadResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1220,7 +1955,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RInitResponse); i {
+			switch v := v.(*RInitRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1232,7 +1967,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RAddPlayerRequest); i {
+			switch v := v.(*RInitResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1244,7 +1979,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RAddPlayerResponse); i {
+			switch v := v.(*RAddPlayerRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1256,7 +1991,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RStartRequest); i {
+			switch v := v.(*RAddPlayerResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1268,7 +2003,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RStartResponse); i {
+			switch v := v.(*RStartRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1280,7 +2015,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RPlayRequest); i {
+			switch v := v.(*RStartResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1292,7 +2027,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RPlayResponse); i {
+			switch v := v.(*RPlayRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1304,7 +2039,7 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RDestroyRequest); i {
+			switch v := v.(*RPlayResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1316,6 +2051,18 @@ func file_game_game_proto_init() {
 			}
 		}
 		file_game_game_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RDestroyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RDestroyResponse); i {
 			case 0:
 				return &v.state
@@ -1327,6 +2074,126 @@ func file_game_game_proto_init() {
 				return nil
 			}
 		}
+		file_game_game_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RUnloadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RUnloadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RBotRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RBotResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RDescribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RDescribeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RListAssetsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RListAssetsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RGetAssetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_game_game_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RGetAssetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1334,7 +2201,7 @@ func file_game_game_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_game_game_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
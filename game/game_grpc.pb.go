@@ -18,6 +18,13 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type InstanceClient interface {
+	// Describe says what sort of game this is. It works with no game loaded.
+	Describe(ctx context.Context, in *RDescribeRequest, opts ...grpc.CallOption) (*RDescribeResponse, error)
+	// ListAssets lists the files that the game has for the server to serve,
+	// i.e. data.json and the web client under web/. It works with no game loaded.
+	ListAssets(ctx context.Context, in *RListAssetsRequest, opts ...grpc.CallOption) (*RListAssetsResponse, error)
+	// GetAsset gets one of the files.
+	GetAsset(ctx context.Context, in *RGetAssetRequest, opts ...grpc.CallOption) (*RGetAssetResponse, error)
 	// Load means find game data and load it.
 	Load(ctx context.Context, in *RLoadRequest, opts ...grpc.CallOption) (*RLoadResponse, error)
 	// Init means create a new game here.
@@ -28,8 +35,13 @@ type InstanceClient interface {
 	Start(ctx context.Context, in *RStartRequest, opts ...grpc.CallOption) (*RStartResponse, error)
 	// Play submits something that should be done in the context of a current turn.
 	Play(ctx context.Context, in *RPlayRequest, opts ...grpc.CallOption) (*RPlayResponse, error)
+	// Bot asks a bot what it would play in a seat. Each seat's bot remembers
+	// what it's tried, for as long as the game is loaded.
+	Bot(ctx context.Context, in *RBotRequest, opts ...grpc.CallOption) (*RBotResponse, error)
 	// Destroy terminates the game and removes all data.
 	Destroy(ctx context.Context, in *RDestroyRequest, opts ...grpc.CallOption) (*RDestroyResponse, error)
+	// Unload takes the game out of the process, but keeps its data.
+	Unload(ctx context.Context, in *RUnloadRequest, opts ...grpc.CallOption) (*RUnloadResponse, error)
 }
 
 type instanceClient struct {
@@ -40,6 +52,33 @@ func NewInstanceClient(cc grpc.ClientConnInterface) InstanceClient {
 	return &instanceClient{cc}
 }
 
+func (c *instanceClient) Describe(ctx context.Context, in *RDescribeRequest, opts ...grpc.CallOption) (*RDescribeResponse, error) {
+	out := new(RDescribeResponse)
+	err := c.cc.Invoke(ctx, "/game.Instance/Describe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instanceClient) ListAssets(ctx context.Context, in *RListAssetsRequest, opts ...grpc.CallOption) (*RListAssetsResponse, error) {
+	out := new(RListAssetsResponse)
+	err := c.cc.Invoke(ctx, "/game.Instance/ListAssets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instanceClient) GetAsset(ctx context.Context, in *RGetAssetRequest, opts ...grpc.CallOption) (*RGetAssetResponse, error) {
+	out := new(RGetAssetResponse)
+	err := c.cc.Invoke(ctx, "/game.Instance/GetAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *instanceClient) Load(ctx context.Context, in *RLoadRequest, opts ...grpc.CallOption) (*RLoadResponse, error) {
 	out := new(RLoadResponse)
 	err := c.cc.Invoke(ctx, "/game.Instance/Load", in, out, opts...)
@@ -85,6 +124,15 @@ func (c *instanceClient) Play(ctx context.Context, in *RPlayRequest, opts ...grp
 	return out, nil
 }
 
+func (c *instanceClient) Bot(ctx context.Context, in *RBotRequest, opts ...grpc.CallOption) (*RBotResponse, error) {
+	out := new(RBotResponse)
+	err := c.cc.Invoke(ctx, "/game.Instance/Bot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *instanceClient) Destroy(ctx context.Context, in *RDestroyRequest, opts ...grpc.CallOption) (*RDestroyResponse, error) {
 	out := new(RDestroyResponse)
 	err := c.cc.Invoke(ctx, "/game.Instance/Destroy", in, out, opts...)
@@ -94,10 +142,26 @@ func (c *instanceClient) Destroy(ctx context.Context, in *RDestroyRequest, opts
 	return out, nil
 }
 
+func (c *instanceClient) Unload(ctx context.Context, in *RUnloadRequest, opts ...grpc.CallOption) (*RUnloadResponse, error) {
+	out := new(RUnloadResponse)
+	err := c.cc.Invoke(ctx, "/game.Instance/Unload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InstanceServer is the server API for Instance service.
 // All implementations must embed UnimplementedInstanceServer
 // for forward compatibility
 type InstanceServer interface {
+	// Describe says what sort of game this is. It works with no game loaded.
+	Describe(context.Context, *RDescribeRequest) (*RDescribeResponse, error)
+	// ListAssets lists the files that the game has for the server to serve,
+	// i.e. data.json and the web client under web/. It works with no game loaded.
+	ListAssets(context.Context, *RListAssetsRequest) (*RListAssetsResponse, error)
+	// GetAsset gets one of the files.
+	GetAsset(context.Context, *RGetAssetRequest) (*RGetAssetResponse, error)
 	// Load means find game data and load it.
 	Load(context.Context, *RLoadRequest) (*RLoadResponse, error)
 	// Init means create a new game here.
@@ -108,8 +172,13 @@ type InstanceServer interface {
 	Start(context.Context, *RStartRequest) (*RStartResponse, error)
 	// Play submits something that should be done in the context of a current turn.
 	Play(context.Context, *RPlayRequest) (*RPlayResponse, error)
+	// Bot asks a bot what it would play in a seat. Each seat's bot remembers
+	// what it's tried, for as long as the game is loaded.
+	Bot(context.Context, *RBotRequest) (*RBotResponse, error)
 	// Destroy terminates the game and removes all data.
 	Destroy(context.Context, *RDestroyRequest) (*RDestroyResponse, error)
+	// Unload takes the game out of the process, but keeps its data.
+	Unload(context.Context, *RUnloadRequest) (*RUnloadResponse, error)
 	mustEmbedUnimplementedInstanceServer()
 }
 
@@ -117,6 +186,15 @@ type InstanceServer interface {
 type UnimplementedInstanceServer struct {
 }
 
+func (UnimplementedInstanceServer) Describe(context.Context, *RDescribeRequest) (*RDescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedInstanceServer) ListAssets(context.Context, *RListAssetsRequest) (*RListAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
+}
+func (UnimplementedInstanceServer) GetAsset(context.Context, *RGetAssetRequest) (*RGetAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAsset not implemented")
+}
 func (UnimplementedInstanceServer) Load(context.Context, *RLoadRequest) (*RLoadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Load not implemented")
 }
@@ -132,9 +210,15 @@ func (UnimplementedInstanceServer) Start(context.Context, *RStartRequest) (*RSta
 func (UnimplementedInstanceServer) Play(context.Context, *RPlayRequest) (*RPlayResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Play not implemented")
 }
+func (UnimplementedInstanceServer) Bot(context.Context, *RBotRequest) (*RBotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Bot not implemented")
+}
 func (UnimplementedInstanceServer) Destroy(context.Context, *RDestroyRequest) (*RDestroyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Destroy not implemented")
 }
+func (UnimplementedInstanceServer) Unload(context.Context, *RUnloadRequest) (*RUnloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unload not implemented")
+}
 func (UnimplementedInstanceServer) mustEmbedUnimplementedInstanceServer() {}
 
 // UnsafeInstanceServer may be embedded to opt out of forward compatibility for this service.
@@ -148,6 +232,60 @@ func RegisterInstanceServer(s grpc.ServiceRegistrar, srv InstanceServer) {
 	s.RegisterService(&Instance_ServiceDesc, srv)
 }
 
+func _Instance_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RDescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstanceServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/game.Instance/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstanceServer).Describe(ctx, req.(*RDescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instance_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RListAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstanceServer).ListAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/game.Instance/ListAssets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstanceServer).ListAssets(ctx, req.(*RListAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Instance_GetAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RGetAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstanceServer).GetAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/game.Instance/GetAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstanceServer).GetAsset(ctx, req.(*RGetAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Instance_Load_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RLoadRequest)
 	if err := dec(in); err != nil {
@@ -238,6 +376,24 @@ func _Instance_Play_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Instance_Bot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RBotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstanceServer).Bot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/game.Instance/Bot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstanceServer).Bot(ctx, req.(*RBotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Instance_Destroy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RDestroyRequest)
 	if err := dec(in); err != nil {
@@ -256,6 +412,24 @@ func _Instance_Destroy_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Instance_Unload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RUnloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstanceServer).Unload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/game.Instance/Unload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstanceServer).Unload(ctx, req.(*RUnloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Instance_ServiceDesc is the grpc.ServiceDesc for Instance service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -263,6 +437,18 @@ var Instance_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "game.Instance",
 	HandlerType: (*InstanceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _Instance_Describe_Handler,
+		},
+		{
+			MethodName: "ListAssets",
+			Handler:    _Instance_ListAssets_Handler,
+		},
+		{
+			MethodName: "GetAsset",
+			Handler:    _Instance_GetAsset_Handler,
+		},
 		{
 			MethodName: "Load",
 			Handler:    _Instance_Load_Handler,
@@ -283,10 +469,18 @@ var Instance_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Play",
 			Handler:    _Instance_Play_Handler,
 		},
+		{
+			MethodName: "Bot",
+			Handler:    _Instance_Bot_Handler,
+		},
 		{
 			MethodName: "Destroy",
 			Handler:    _Instance_Destroy_Handler,
 		},
+		{
+			MethodName: "Unload",
+			Handler:    _Instance_Unload_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "game/game.proto",
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"math/rand"
 	"net"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -22,9 +25,11 @@ func WrapChanges(in []Change) []*RChange {
 	var out []*RChange
 	for _, c := range in {
 		out = append(out, &RChange{
-			Who:   c.Who,
-			What:  c.What,
-			Where: c.Where,
+			Who:    c.Who,
+			What:   c.What,
+			Where:  c.Where,
+			Code:   c.Code,
+			Params: WrapParams(c.Params),
 		})
 	}
 
@@ -35,15 +40,35 @@ func UnwrapChanges(in []*RChange) []Change {
 	var out []Change
 	for _, c := range in {
 		out = append(out, Change{
-			Who:   c.Who,
-			What:  c.What,
-			Where: c.Where,
+			Who:    c.Who,
+			What:   c.What,
+			Where:  c.Where,
+			Code:   c.Code,
+			Params: UnwrapParams(c.Params),
 		})
 	}
 
 	return out
 }
 
+// WrapParams makes JSON of a change's params, or nil if there are none.
+func WrapParams(in Params) []byte {
+	if len(in) == 0 {
+		return nil
+	}
+	out, _ := json.Marshal(in)
+	return out
+}
+
+func UnwrapParams(in []byte) Params {
+	if len(in) == 0 {
+		return nil
+	}
+	out := Params{}
+	json.Unmarshal(in, &out)
+	return out
+}
+
 func WrapGameState(in *GameState) *RGameState {
 	global, _ := json.Marshal(in.Global)
 
@@ -135,26 +160,81 @@ func UnwrapTurnState(in *RTurnState) *TurnState {
 	}
 }
 
+// ErrorToGRPC makes a gRPC error from a game error, with the code in the
+// details, so that it can be made back into the same game error.
 func ErrorToGRPC(err error) error {
-	if err, ok := err.(*GameError); ok {
-		switch err.Code {
-		case StatusBadRequest:
-			return status.Errorf(codes.InvalidArgument, "%v", err.Message)
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		// already a gRPC error
+		return err
+	}
+
+	code := Code(err)
+	msg := err.Error()
+	if ge, ok := err.(*GameError); ok {
+		msg = ge.Message
+	}
+
+	var gcode codes.Code
+	switch code {
+	case StatusBadRequest:
+		gcode = codes.InvalidArgument
+	case StatusConflict:
+		gcode = codes.AlreadyExists
+	case StatusUnknown:
+		gcode = codes.Unknown
+	default:
+		gcode = codes.FailedPrecondition
+	}
+
+	st, derr := status.New(gcode, msg).WithDetails(&RErrorDetail{Code: string(code)})
+	if derr != nil {
+		return status.Error(gcode, msg)
+	}
+	return st.Err()
+}
+
+// ErrorFromGRPC gets a game error back from a gRPC error, if it was one.
+// Other errors are as they were.
+func ErrorFromGRPC(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, d := range st.Details() {
+		if detail, ok := d.(*RErrorDetail); ok {
+			return &GameError{StatusCode(detail.Code), st.Message()}
 		}
 	}
-	return status.Errorf(codes.FailedPrecondition, "%v", err)
+	return err
 }
 
 type NewGameFunc func(map[string]interface{}) (Game, error)
 type LoadGameFunc func(io.Reader) (Game, error)
 
-func GRPCMain(newGame NewGameFunc, loadGame LoadGameFunc) {
+// NewBotFunc makes a bot of one of the kinds in the description, to play a
+// seat.
+type NewBotFunc func(kind, player string) (Bot, error)
+
+// GRPCOption is something extra for a GRPCServer.
+type GRPCOption func(*GRPCServer)
+
+// WithBots gives the server bots, which the description should list the kinds
+// of.
+func WithBots(newBot NewBotFunc) GRPCOption {
+	return func(s *GRPCServer) {
+		s.newBot = newBot
+	}
+}
+
+// GRPCMain runs a game plugin. Assets are files for the server to serve, and
+// may be nil.
+func GRPCMain(desc Description, assets fs.FS, newGame NewGameFunc, loadGame LoadGameFunc, opts ...GRPCOption) {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
 	bind := os.Args[1]
 
-	gsrv, err := NewGRPCServer(bind, newGame, loadGame)
+	gsrv, err := NewGRPCServer(bind, desc, assets, newGame, loadGame, opts...)
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -169,30 +249,52 @@ func GRPCMain(newGame NewGameFunc, loadGame LoadGameFunc) {
 	}
 }
 
+// GRPCServer serves games of one type, any number of them at once, each under
+// its own lock, so that one process can host many games.
 type GRPCServer struct {
 	UnimplementedInstanceServer
 
+	desc     Description
+	assets   fs.FS
 	newGame  NewGameFunc
 	loadGame LoadGameFunc
+	newBot   NewBotFunc
 
 	listener net.Listener
 
+	mu    sync.Mutex
+	games map[string]*hostedGame
+}
+
+// hostedGame is one game in a GRPCServer. gg is nil until the game has been
+// made or loaded, and once it has gone.
+type hostedGame struct {
+	mu sync.Mutex
 	id string
 	gg Game
+	// bots playing seats, which go when the game does
+	bots map[string]Bot
 }
 
-func NewGRPCServer(bind string, newGame NewGameFunc, loadGame LoadGameFunc) (*GRPCServer, error) {
+func NewGRPCServer(bind string, desc Description, assets fs.FS, newGame NewGameFunc, loadGame LoadGameFunc, opts ...GRPCOption) (*GRPCServer, error) {
 	binds := strings.SplitN(bind, ":", 2)
 
 	l, err := net.Listen(binds[0], binds[1])
 	if err != nil {
 		return nil, err
 	}
-	return &GRPCServer{
+	s := &GRPCServer{
+		desc:     desc,
+		assets:   assets,
 		newGame:  newGame,
 		loadGame: loadGame,
 		listener: l,
-	}, nil
+		games:    map[string]*hostedGame{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 func (s *GRPCServer) StartServer(ctx context.Context) error {
@@ -202,27 +304,125 @@ func (s *GRPCServer) StartServer(ctx context.Context) error {
 	return srv.Serve(s.listener)
 }
 
+func (s *GRPCServer) Describe(ctx context.Context, req *RDescribeRequest) (*RDescribeResponse, error) {
+	return WrapDescription(&s.desc), nil
+}
+
+func (s *GRPCServer) ListAssets(ctx context.Context, req *RListAssetsRequest) (*RListAssetsResponse, error) {
+	res := &RListAssetsResponse{}
+	if s.assets == nil {
+		return res, nil
+	}
+	err := fs.WalkDir(s.assets, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			res.Paths = append(res.Paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *GRPCServer) GetAsset(ctx context.Context, req *RGetAssetRequest) (*RGetAssetResponse, error) {
+	if !fs.ValidPath(req.Path) {
+		return nil, status.Errorf(codes.InvalidArgument, "bad path: %s", req.Path)
+	}
+	if s.assets == nil {
+		return nil, status.Errorf(codes.NotFound, "no assets")
+	}
+	data, err := fs.ReadFile(s.assets, req.Path)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return &RGetAssetResponse{Data: data}, nil
+}
+
+// Games is the ids of the games that are in the server now.
+func (s *GRPCServer) Games() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []string
+	for id := range s.games {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// reserveGame puts in a locked game with no Game yet, if there isn't one with
+// the same id, so that nothing else can make or load the same game while it's
+// being made. The caller must fill it in, or remove it, and then unlock it.
+func (s *GRPCServer) reserveGame(id string) (*hostedGame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.games[id]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "game already present: %s", id)
+	}
+	g := &hostedGame{id: id, bots: map[string]Bot{}}
+	g.mu.Lock()
+	s.games[id] = g
+	return g, nil
+}
+
+// lockGame finds a game and locks it. The caller must unlock it.
+func (s *GRPCServer) lockGame(id string) (*hostedGame, error) {
+	s.mu.Lock()
+	g, ok := s.games[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no game: %s", id)
+	}
+
+	g.mu.Lock()
+	if g.gg == nil {
+		// removed while waiting for the lock
+		g.mu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "no game: %s", id)
+	}
+	return g, nil
+}
+
+// removeGame takes a locked game out of the server.
+func (s *GRPCServer) removeGame(g *hostedGame) {
+	s.mu.Lock()
+	delete(s.games, g.id)
+	s.mu.Unlock()
+
+	g.gg = nil
+	g.bots = nil
+}
+
 func (s *GRPCServer) Load(ctx context.Context, req *RLoadRequest) (*RLoadResponse, error) {
-	if s.gg != nil {
-		return nil, status.Errorf(codes.AlreadyExists, "game already present")
+	g, err := s.reserveGame(req.Id)
+	if err != nil {
+		return nil, err
 	}
+	defer g.mu.Unlock()
 
 	f, err := os.Open(saveFileName(req.Id))
 	if err != nil {
-		log.Error().Err(err).Msg("cannot open state file")
+		log.Error().Err(err).Str("game", req.Id).Msg("cannot open state file")
+		s.removeGame(g)
 		return nil, err
 	}
+	defer f.Close()
 
 	gg, err := s.loadGame(f)
 	if err != nil {
-		log.Error().Err(err).Msg("cannot restore state")
+		log.Error().Err(err).Str("game", req.Id).Msg("cannot restore state")
+		s.removeGame(g)
 		return nil, err
 	}
+	g.gg = gg
 
-	s.id = req.Id
-	s.gg = gg
-
-	sg := s.gg.GetGameState()
+	sg := gg.GetGameState()
 
 	return &RLoadResponse{
 		State: WrapGameState(&sg),
@@ -230,27 +430,36 @@ func (s *GRPCServer) Load(ctx context.Context, req *RLoadRequest) (*RLoadRespons
 }
 
 func (s *GRPCServer) Init(ctx context.Context, req *RInitRequest) (*RInitResponse, error) {
-	if s.gg != nil {
-		return nil, status.Errorf(codes.AlreadyExists, "game already present")
-	}
-
 	options := map[string]interface{}{}
 	err := json.Unmarshal(req.Options, &options)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "bad options json")
 	}
 
-	s.id = req.Id
-	gg, _ := s.newGame(options)
-	s.gg = gg
+	// the id is taken before the save is written, so that only one Init can
+	// write it
+	g, err := s.reserveGame(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer g.mu.Unlock()
+
+	gg, err := s.newGame(options)
+	if err != nil {
+		s.removeGame(g)
+		return nil, ErrorToGRPC(err)
+	}
 
-	err = s.saveGame()
+	err = saveGame(req.Id, gg)
 	if err != nil {
-		// XXX - this and others are dangerous, as they break sync
-		log.Error().Err(err).Msg("save failed")
+		// without a save, the game would be gone after a restart
+		log.Error().Err(err).Str("game", req.Id).Msg("save failed")
+		s.removeGame(g)
+		return nil, status.Error(codes.Internal, "cannot save")
 	}
+	g.gg = gg
 
-	sg := s.gg.GetGameState()
+	sg := gg.GetGameState()
 
 	return &RInitResponse{
 		State: WrapGameState(&sg),
@@ -258,52 +467,51 @@ func (s *GRPCServer) Init(ctx context.Context, req *RInitRequest) (*RInitRespons
 }
 
 func (s *GRPCServer) AddPlayer(ctx context.Context, req *RAddPlayerRequest) (*RAddPlayerResponse, error) {
-	if s.gg == nil {
-		panic("no game")
+	g, err := s.lockGame(req.Game)
+	if err != nil {
+		return nil, err
 	}
+	defer g.mu.Unlock()
 
 	options := map[string]interface{}{}
-	err := json.Unmarshal(req.Options, &options)
+	err = json.Unmarshal(req.Options, &options)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "bad options json")
 	}
 
-	err = s.gg.AddPlayer(req.Name, options)
+	err = g.gg.AddPlayer(req.Name, options)
 	if err != nil {
 		return nil, ErrorToGRPC(err)
 	}
-	err = s.saveGame()
+	err = saveGame(g.id, g.gg)
 	if err != nil {
-		log.Error().Err(err).Msg("save failed")
+		log.Error().Err(err).Str("game", g.id).Msg("save failed")
 	}
 
-	sg := s.gg.GetGameState()
+	sg := g.gg.GetGameState()
 
 	return &RAddPlayerResponse{
 		State: WrapGameState(&sg),
 	}, nil
 }
 
-func (s *GRPCServer) Start(context.Context, *RStartRequest) (*RStartResponse, error) {
-	if s.gg == nil {
-		panic("no game")
+func (s *GRPCServer) Start(ctx context.Context, req *RStartRequest) (*RStartResponse, error) {
+	g, err := s.lockGame(req.Game)
+	if err != nil {
+		return nil, err
 	}
+	defer g.mu.Unlock()
 
-	err := s.gg.Start()
+	err = g.gg.Start()
 	if err != nil {
-		switch Code(err) {
-		case StatusBadRequest:
-			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
-		default:
-			return nil, status.Errorf(codes.Unknown, "%v", err)
-		}
+		return nil, ErrorToGRPC(err)
 	}
-	err = s.saveGame()
+	err = saveGame(g.id, g.gg)
 	if err != nil {
-		log.Error().Err(err).Msg("save failed")
+		log.Error().Err(err).Str("game", g.id).Msg("save failed")
 	}
 
-	sg := s.gg.GetGameState()
+	sg := g.gg.GetGameState()
 
 	return &RStartResponse{
 		State: WrapGameState(&sg),
@@ -311,32 +519,27 @@ func (s *GRPCServer) Start(context.Context, *RStartRequest) (*RStartResponse, er
 }
 
 func (s *GRPCServer) Play(ctx context.Context, in *RPlayRequest) (*RPlayResponse, error) {
-	if s.gg == nil {
-		panic("no game")
+	g, err := s.lockGame(in.Game)
+	if err != nil {
+		return nil, err
 	}
+	defer g.mu.Unlock()
 
-	res, err := s.gg.Play(in.Player, Command{
+	res, err := g.gg.Play(in.Player, Command{
 		Command: CommandString(in.Command),
 		Options: in.Options,
 	})
 	if err != nil {
-		switch Code(err) {
-		case StatusNotStarted, StatusNotYourTurn, StatusNotNow, StatusMustDo, StatusWrongPhase:
-			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
-		case StatusBadRequest:
-			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
-		default:
-			return nil, status.Errorf(codes.Unknown, "%v", err)
-		}
+		return nil, ErrorToGRPC(err)
 	}
-	err = s.saveGame()
+	err = saveGame(g.id, g.gg)
 	if err != nil {
-		log.Error().Err(err).Msg("save failed")
+		log.Error().Err(err).Str("game", g.id).Msg("save failed")
 	}
 
 	rr, _ := json.Marshal(res.Response)
 
-	sg := s.gg.GetGameState()
+	sg := g.gg.GetGameState()
 
 	return &RPlayResponse{
 		Response: rr,
@@ -345,49 +548,91 @@ func (s *GRPCServer) Play(ctx context.Context, in *RPlayRequest) (*RPlayResponse
 	}, nil
 }
 
-func (s *GRPCServer) Destroy(context.Context, *RDestroyRequest) (*RDestroyResponse, error) {
-	if s.gg == nil {
-		panic("no game")
+// hasBot says whether there's a kind of bot.
+func (s *GRPCServer) hasBot(kind string) bool {
+	if s.newBot == nil {
+		return false
+	}
+	for _, k := range s.desc.Bots {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *GRPCServer) Bot(ctx context.Context, req *RBotRequest) (*RBotResponse, error) {
+	if !s.hasBot(req.Kind) {
+		return nil, status.Errorf(codes.InvalidArgument, "no such bot: %s", req.Kind)
 	}
 
-	err := s.wipeGame()
+	update := GameUpdate{}
+	err := json.Unmarshal(req.Update, &update)
 	if err != nil {
-		log.Error().Err(err).Msg("cannot delete")
-		return nil, status.Error(codes.Internal, "cannot delete")
+		return nil, status.Error(codes.InvalidArgument, "bad update json")
 	}
-	s.gg = nil
 
-	return &RDestroyResponse{}, nil
+	g, err := s.lockGame(req.Game)
+	if err != nil {
+		return nil, err
+	}
+	defer g.mu.Unlock()
+
+	bot, ok := g.bots[req.Player]
+	if !ok {
+		bot, err = s.newBot(req.Kind, req.Player)
+		if err != nil {
+			return nil, ErrorToGRPC(err)
+		}
+		g.bots[req.Player] = bot
+	}
+
+	res := &RBotResponse{}
+	if cmd := bot.Play(update); cmd != nil {
+		res.Command = string(cmd.Command)
+		res.Options = cmd.Options
+	}
+	return res, nil
 }
 
-func (s *GRPCServer) saveGame() error {
-	if s.gg == nil {
-		panic("no game")
+func (s *GRPCServer) Destroy(ctx context.Context, req *RDestroyRequest) (*RDestroyResponse, error) {
+	g, err := s.lockGame(req.Game)
+	if err != nil {
+		return nil, err
 	}
+	defer g.mu.Unlock()
 
-	outFile, err := os.Create(saveFileName(s.id))
+	err = os.Remove(saveFileName(g.id))
 	if err != nil {
-		return err
+		log.Error().Err(err).Str("game", g.id).Msg("cannot delete")
+		return nil, status.Error(codes.Internal, "cannot delete")
 	}
-	defer outFile.Close()
+	s.removeGame(g)
 
-	return s.gg.WriteOut(outFile)
+	return &RDestroyResponse{}, nil
 }
 
-func (s *GRPCServer) wipeGame() error {
-	if s.gg == nil {
-		panic("no game")
+func (s *GRPCServer) Unload(ctx context.Context, req *RUnloadRequest) (*RUnloadResponse, error) {
+	g, err := s.lockGame(req.Game)
+	if err != nil {
+		return nil, err
 	}
+	defer g.mu.Unlock()
 
-	err := os.Remove(saveFileName(s.id))
+	// every change was saved as it was made, so there's nothing more to do
+	s.removeGame(g)
+
+	return &RUnloadResponse{}, nil
+}
+
+func saveGame(id string, gg Game) error {
+	outFile, err := os.Create(saveFileName(id))
 	if err != nil {
 		return err
 	}
+	defer outFile.Close()
 
-	s.id = ""
-	s.gg = nil
-
-	return nil
+	return gg.WriteOut(outFile)
 }
 
 func saveFileName(id string) string {
@@ -0,0 +1,240 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// countGame counts the moves made in it.
+type countGame struct {
+	Count int `json:"count"`
+}
+
+func (g *countGame) AddPlayer(name string, options map[string]interface{}) error { return nil }
+func (g *countGame) Start() error                                                { return nil }
+
+func (g *countGame) Play(player string, c Command) (PlayResult, error) {
+	g.Count++
+	return PlayResult{Response: g.Count}, nil
+}
+
+func (g *countGame) GetGameState() GameState {
+	return GameState{Status: StatusInProgress, TurnNumber: g.Count}
+}
+
+func (g *countGame) WriteOut(w io.Writer) error {
+	return json.NewEncoder(w).Encode(g)
+}
+
+func TestGRPCServerGames(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+	os.Mkdir("save", 0755)
+
+	s := &GRPCServer{
+		newGame: func(map[string]interface{}) (Game, error) {
+			return &countGame{}, nil
+		},
+		loadGame: func(r io.Reader) (Game, error) {
+			g := &countGame{}
+			return g, json.NewDecoder(r).Decode(g)
+		},
+		games: map[string]*hostedGame{},
+	}
+	ctx := context.Background()
+
+	for _, id := range []string{"a", "b"} {
+		if _, err := s.Init(ctx, &RInitRequest{Id: id, Options: []byte("{}")}); err != nil {
+			t.Fatalf("init %s: %v", id, err)
+		}
+	}
+	if _, err := s.Init(ctx, &RInitRequest{Id: "a", Options: []byte("{}")}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected already exists, got %v", err)
+	}
+
+	// games are played at the same time, each one in order
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "a", "a", "b"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if _, err := s.Play(ctx, &RPlayRequest{Game: id, Command: "move"}); err != nil {
+				t.Errorf("play %s: %v", id, err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	res, err := s.Play(ctx, &RPlayRequest{Game: "a", Command: "move"})
+	if err != nil || res.State.TurnNumber != 4 {
+		t.Errorf("expected a at 4, got %v %v", res, err)
+	}
+	if _, err := s.Play(ctx, &RPlayRequest{Game: "c", Command: "move"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	// unload keeps the save, so the game can come back
+	if _, err := s.Unload(ctx, &RUnloadRequest{Game: "a"}); err != nil {
+		t.Fatalf("unload: %v", err)
+	}
+	if _, err := s.Play(ctx, &RPlayRequest{Game: "a", Command: "move"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found after unload, got %v", err)
+	}
+	lres, err := s.Load(ctx, &RLoadRequest{Id: "a"})
+	if err != nil || lres.State.TurnNumber != 4 {
+		t.Errorf("expected a loaded at 4, got %v %v", lres, err)
+	}
+
+	// destroy takes the save too
+	if _, err := s.Destroy(ctx, &RDestroyRequest{Game: "b"}); err != nil {
+		t.Fatalf("destroy: %v", err)
+	}
+	if _, err := os.Stat(saveFileName("b")); !os.IsNotExist(err) {
+		t.Errorf("expected save gone, got %v", err)
+	}
+	if games := s.Games(); len(games) != 1 || games[0] != "a" {
+		t.Errorf("expected only a, got %v", games)
+	}
+}
+
+// countBot says how many times it's been asked.
+type countBot struct {
+	n int
+}
+
+func (b *countBot) Play(update GameUpdate) *Command {
+	b.n++
+	return &Command{Command: "move", Options: strconv.Itoa(b.n)}
+}
+
+func TestGRPCServerBot(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+	os.Mkdir("save", 0755)
+
+	s := &GRPCServer{
+		desc: Description{Bots: []string{"counter"}},
+		newGame: func(map[string]interface{}) (Game, error) {
+			return &countGame{}, nil
+		},
+		loadGame: func(r io.Reader) (Game, error) {
+			g := &countGame{}
+			return g, json.NewDecoder(r).Decode(g)
+		},
+		newBot: func(kind, player string) (Bot, error) {
+			return &countBot{}, nil
+		},
+		games: map[string]*hostedGame{},
+	}
+	ctx := context.Background()
+
+	if _, err := s.Init(ctx, &RInitRequest{Id: "a", Options: []byte("{}")}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	ask := func(player, kind string) (*RBotResponse, error) {
+		return s.Bot(ctx, &RBotRequest{Game: "a", Player: player, Kind: kind, Update: []byte("{}")})
+	}
+
+	// each seat has its own bot, which remembers
+	for _, want := range []string{"1", "2"} {
+		if res, err := ask("robo", "counter"); err != nil || res.Command != "move" || res.Options != want {
+			t.Errorf("expected move %s, got %v %v", want, res, err)
+		}
+	}
+	if res, err := ask("other", "counter"); err != nil || res.Options != "1" {
+		t.Errorf("expected a new bot, got %v %v", res, err)
+	}
+	if _, err := ask("robo", "genius"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected bad kind, got %v", err)
+	}
+
+	// bots go with the game
+	s.Unload(ctx, &RUnloadRequest{Game: "a"})
+	if _, err := ask("robo", "counter"); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+	s.Load(ctx, &RLoadRequest{Id: "a"})
+	if res, err := ask("robo", "counter"); err != nil || res.Options != "1" {
+		t.Errorf("expected a new bot after load, got %v %v", res, err)
+	}
+}
+
+func TestGRPCServerInit_race(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	made := make(chan int, 2)
+	s := &GRPCServer{
+		newGame: func(options map[string]interface{}) (Game, error) {
+			n := int(options["n"].(float64))
+			made <- n
+			return &countGame{Count: n}, nil
+		},
+		games: map[string]*hostedGame{},
+	}
+	ctx := context.Background()
+
+	// with no save dir, saving fails, and the game is taken out again
+	if _, err := s.Init(ctx, &RInitRequest{Id: "a", Options: []byte(`{"n":1}`)}); status.Code(err) != codes.Internal {
+		t.Errorf("expected internal error, got %v", err)
+	}
+	<-made
+	if games := s.Games(); len(games) != 0 {
+		t.Errorf("expected no games, got %v", games)
+	}
+	os.Mkdir("save", 0755)
+
+	// only one of two at once gets to make the game, and write its save
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, n := range []string{"2", "3"} {
+		wg.Add(1)
+		go func(n string) {
+			defer wg.Done()
+			_, err := s.Init(ctx, &RInitRequest{Id: "a", Options: []byte(`{"n":` + n + `}`)})
+			errs <- err
+		}(n)
+	}
+	wg.Wait()
+	close(made)
+	close(errs)
+
+	var failed int
+	for err := range errs {
+		if status.Code(err) == codes.AlreadyExists {
+			failed++
+		} else if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	winner := <-made
+	if failed != 1 || len(made) != 0 {
+		t.Fatalf("expected one game made, got %d failures", failed)
+	}
+
+	res, err := s.Play(ctx, &RPlayRequest{Game: "a", Command: "move"})
+	if err != nil || int(res.State.TurnNumber) != winner+1 {
+		t.Errorf("expected game from %d, got %v %v", winner, res, err)
+	}
+	data, _ := os.ReadFile(saveFileName("a"))
+	saved := &countGame{}
+	json.Unmarshal(data, saved)
+	if saved.Count != winner+1 {
+		t.Errorf("expected save from %d, got %d", winner, saved.Count)
+	}
+}
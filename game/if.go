@@ -46,11 +46,16 @@ type TurnState struct {
 	Custom interface{} `json:"custom"`
 }
 
-// Change is something that happened
+// Change is something that happened. What is always set, in English, but
+// clients can describe the change in their own words from the code and params.
 type Change struct {
 	Who   string `json:"who"`
 	What  string `json:"what"`
 	Where string `json:"where"`
+	// Code says what sort of thing happened, for looking up in a Catalogue
+	Code string `json:"code,omitempty"`
+	// Params are the details, e.g. place ids and amounts
+	Params Params `json:"params,omitempty"`
 }
 
 // PlayResult is the result of a Game.Play() call
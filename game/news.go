@@ -0,0 +1,91 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Params are the details of a change, by name, as in the messages that
+// describe it.
+type Params map[string]interface{}
+
+// Namer gives the name of something that news refers to by id, e.g. a place.
+// The kinds are up to each game.
+type Namer func(kind, id string) string
+
+// Catalogue has messages for describing news, by change code, in one
+// language. Messages refer to params in braces, with an optional kind for the
+// Namer, e.g. "buys a ticket to {to:place}".
+type Catalogue map[string]string
+
+// ReadCatalogue reads the messages for a language from messages/<lang>.json in
+// some files, e.g. a game's embedded assets.
+func ReadCatalogue(fsys fs.FS, lang string) (Catalogue, error) {
+	data, err := fs.ReadFile(fsys, path.Join("messages", lang+".json"))
+	if err != nil {
+		return nil, err
+	}
+	c := Catalogue{}
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return nil, fmt.Errorf("bad messages for %s: %v", lang, err)
+	}
+	return c, nil
+}
+
+// Format makes the message for a code, or returns false if there is no
+// message, or the params don't fit it. names can be nil, to use ids as they
+// are.
+func (c Catalogue) Format(code string, params Params, names Namer) (string, bool) {
+	msg, ok := c[code]
+	if !ok {
+		return "", false
+	}
+
+	var out strings.Builder
+	for {
+		start := strings.IndexByte(msg, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(msg[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name, kind := msg[start+1:end], ""
+		if i := strings.IndexByte(name, ':'); i >= 0 {
+			name, kind = name[:i], name[i+1:]
+		}
+		v, ok := params[name]
+		if !ok {
+			return "", false
+		}
+		value := fmt.Sprint(v)
+		if kind != "" && names != nil {
+			value = names(kind, value)
+		}
+
+		out.WriteString(msg[:start])
+		out.WriteString(value)
+		msg = msg[end+1:]
+	}
+	out.WriteString(msg)
+
+	return out.String(), true
+}
+
+// Describe says what a change was, in the catalogue's words if it can, or
+// else as it came.
+func (c Catalogue) Describe(ch Change, names Namer) string {
+	if ch.Code != "" {
+		if msg, ok := c.Format(ch.Code, ch.Params, names); ok {
+			return msg
+		}
+	}
+	return ch.What
+}
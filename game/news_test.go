@@ -0,0 +1,61 @@
+package game
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestCatalogue_describe(t *testing.T) {
+	c := Catalogue{
+		"buy":  "buys {n} {thing:thing}",
+		"stop": "stops",
+	}
+	names := func(kind, id string) string { return kind + "-" + id }
+
+	what := c.Describe(Change{What: "fallback", Code: "buy", Params: Params{"n": 2, "thing": "hat"}}, names)
+	if what != "buys 2 thing-hat" {
+		t.Errorf("bad describe: %q", what)
+	}
+
+	// numbers come back from JSON as floats
+	what = c.Describe(Change{Code: "buy", Params: Params{"n": 2.0, "thing": "hat"}}, nil)
+	if what != "buys 2 hat" {
+		t.Errorf("bad describe: %q", what)
+	}
+
+	what = c.Describe(Change{What: "fallback", Code: "stop"}, names)
+	if what != "stops" {
+		t.Errorf("bad describe: %q", what)
+	}
+
+	for _, ch := range []Change{
+		{What: "fallback"},
+		{What: "fallback", Code: "other"},
+		{What: "fallback", Code: "buy", Params: Params{"n": 2}},
+	} {
+		if what := c.Describe(ch, names); what != "fallback" {
+			t.Errorf("should fall back: %q", what)
+		}
+	}
+}
+
+func TestReadCatalogue(t *testing.T) {
+	fsys := fstest.MapFS{
+		"messages/de.json": {Data: []byte(`{"stop":"hält an"}`)},
+		"messages/xx.json": {Data: []byte(`nonsense`)},
+	}
+
+	c, err := ReadCatalogue(fsys, "de")
+	if err != nil || c["stop"] != "hält an" {
+		t.Errorf("bad catalogue: %v %v", c, err)
+	}
+
+	_, err = ReadCatalogue(fsys, "xx")
+	if err == nil {
+		t.Errorf("expected error")
+	}
+	_, err = ReadCatalogue(fsys, "fr")
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
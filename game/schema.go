@@ -0,0 +1,193 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Description is what a game type says about itself.
+type Description struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	MinPlayers  int    `json:"minPlayers"`
+	MaxPlayers  int    `json:"maxPlayers"`
+	// Version is of the save format, and changes when old saves won't load
+	Version string `json:"version,omitempty"`
+
+	// JSON Schemas, for the game options, and each player's options
+	Options       json.RawMessage `json:"options,omitempty"`
+	PlayerOptions json.RawMessage `json:"playerOptions,omitempty"`
+
+	// Bots are the kinds of bot that the game has, for playing seats
+	Bots []string `json:"bots,omitempty"`
+}
+
+func WrapDescription(in *Description) *RDescribeResponse {
+	return &RDescribeResponse{
+		Name:          in.Name,
+		DisplayName:   in.DisplayName,
+		MinPlayers:    int32(in.MinPlayers),
+		MaxPlayers:    int32(in.MaxPlayers),
+		Options:       in.Options,
+		PlayerOptions: in.PlayerOptions,
+		Version:       in.Version,
+		Bots:          in.Bots,
+	}
+}
+
+func UnwrapDescription(in *RDescribeResponse) *Description {
+	return &Description{
+		Name:          in.Name,
+		DisplayName:   in.DisplayName,
+		MinPlayers:    int(in.MinPlayers),
+		MaxPlayers:    int(in.MaxPlayers),
+		Options:       json.RawMessage(in.Options),
+		PlayerOptions: json.RawMessage(in.PlayerOptions),
+		Version:       in.Version,
+		Bots:          in.Bots,
+	}
+}
+
+// Schema is the part of JSON Schema that games need for describing options:
+// type, enum, minimum, maximum, properties, required, additionalProperties
+// (only as a boolean) and items.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// ValidateJSON checks some JSON against a schema, also in JSON. An empty schema
+// allows anything, and empty JSON is taken to be an empty object.
+func ValidateJSON(schema, data json.RawMessage) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	s := &Schema{}
+	err := json.Unmarshal(schema, s)
+	if err != nil {
+		return fmt.Errorf("bad schema: %w", err)
+	}
+
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	var v interface{}
+	err = json.Unmarshal(data, &v)
+	if err != nil {
+		return Error(StatusBadRequest, "bad json")
+	}
+
+	return s.Validate(v)
+}
+
+// Validate checks a value, as decoded from JSON into an interface{}.
+func (s *Schema) Validate(v interface{}) error {
+	return s.validate("", v)
+}
+
+func (s *Schema) validate(at string, v interface{}) error {
+	bad := func(format string, a ...interface{}) error {
+		where := at
+		if where == "" {
+			where = "options"
+		}
+		return Errorf(StatusBadRequest, "%s: %s", where, fmt.Sprintf(format, a...))
+	}
+
+	if s.Type != "" && !isJSONType(s.Type, v) {
+		return bad("must be %s", s.Type)
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return bad("must be one of %v", s.Enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return bad("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return bad("must be at most %v", *s.Maximum)
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return bad("%s is required", name)
+			}
+		}
+		// sorted, so errors are the same every time
+		var names []string
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ps, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return bad("unknown option %s", name)
+				}
+				continue
+			}
+			err := ps.validate(joinPath(at, name), v[name])
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				err := s.Items.validate(joinPath(at, fmt.Sprint(i)), item)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func isJSONType(t string, v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case float64:
+		return t == "number" || (t == "integer" && v == math.Trunc(v))
+	case map[string]interface{}:
+		return t == "object"
+	case []interface{}:
+		return t == "array"
+	}
+	return false
+}
+
+func joinPath(at, name string) string {
+	if at == "" {
+		return name
+	}
+	return at + "." + name
+}
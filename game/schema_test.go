@@ -0,0 +1,58 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"goal": {"type": "integer", "minimum": 1, "maximum": 10},
+		"colour": {"type": "string", "enum": ["red", "blue"]},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["colour"],
+	"additionalProperties": false
+}`
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		ok   bool
+	}{
+		{`{"colour":"red"}`, true},
+		{`{"colour":"red","goal":4,"tags":["a","b"]}`, true},
+		{`{"goal":4}`, false},
+		{`{"colour":"green"}`, false},
+		{`{"colour":"red","goal":4.5}`, false},
+		{`{"colour":"red","goal":0}`, false},
+		{`{"colour":"red","goal":11}`, false},
+		{`{"colour":"red","tags":[1]}`, false},
+		{`{"colour":"red","other":1}`, false},
+		{`[]`, false},
+		{``, false},
+		{`nonsense`, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateJSON(json.RawMessage(testSchema), json.RawMessage(tt.data))
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.data, err)
+		}
+		if !tt.ok {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.data)
+			} else if Code(err) != StatusBadRequest {
+				t.Errorf("%s: bad error: %v", tt.data, err)
+			}
+		}
+	}
+}
+
+func TestValidateJSON_noSchema(t *testing.T) {
+	err := ValidateJSON(nil, json.RawMessage(`{"anything":true}`))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+// Package goassets has the files that go with the go game: its data, messages
+// for describing news, and the web client, so that the game can be deployed as
+// one binary.
+package goassets
+
+import "embed"
+
+//go:embed data.json messages web
+var FS embed.FS
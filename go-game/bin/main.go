@@ -2,18 +2,20 @@ package main
 
 import (
 	"io"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/undeconstructed/gogogo/game"
+	"github.com/undeconstructed/gogogo/go-game"
 	"github.com/undeconstructed/gogogo/go-game/lib"
 )
 
 func main() {
-	data := gogame.LoadJson(".")
+	data := gogame.LoadJsonFS(goassets.FS)
 
-	game.GRPCMain(func(options map[string]interface{}) (game.Game, error) {
+	game.GRPCMain(gogame.Description(data), goassets.FS, func(options map[string]interface{}) (game.Game, error) {
 		goal := 4
 		if g0, ok := options["goal"]; ok {
 			if g1, ok := g0.(float64); ok {
@@ -26,5 +28,11 @@ func main() {
 		return gogame.NewGame(data, goal), nil
 	}, func(in io.Reader) (game.Game, error) {
 		return gogame.NewFromSaved(data, in)
-	})
+	}, game.WithBots(func(kind, player string) (game.Bot, error) {
+		level, err := gogame.ParseLevel(kind)
+		if err != nil {
+			return nil, err
+		}
+		return gogame.NewAI(data, player, level, time.Now().UnixNano()), nil
+	}))
 }
@@ -0,0 +1,776 @@
+package gogame
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// Level is how hard the AI tries.
+type Level int
+
+const (
+	// LevelEasy wanders about, buying whatever souvenirs it finds.
+	LevelEasy Level = iota
+	// LevelNormal plans a route, and uses luck cards when it has to.
+	LevelNormal
+	// LevelHard plans the best route it can, and plays every card it has.
+	LevelHard
+)
+
+// ParseLevel reads a level name.
+func ParseLevel(s string) (Level, error) {
+	switch s {
+	case "easy":
+		return LevelEasy, nil
+	case "normal", "":
+		return LevelNormal, nil
+	case "hard":
+		return LevelHard, nil
+	default:
+		return 0, fmt.Errorf("unknown level: %s", s)
+	}
+}
+
+const (
+	// aiDotCost is what the AI thinks each dot of a journey costs, in the same
+	// units as fares, because the turns are worth something
+	aiDotCost = 10
+	// aiReserve is how much local money the AI likes to keep, for hotel bills
+	aiReserve = 100
+	// aiEasyWander is the chance of an easy AI doing something random
+	aiEasyWander = 0.25
+)
+
+// aiHop is a ticket that can be bought.
+type aiHop struct {
+	to    string
+	modes string
+	price int
+	dots  int
+}
+
+func (h aiHop) cost() int {
+	return h.price + aiDotCost*h.dots
+}
+
+// AI plays go for one player. It implements game.Bot.
+type AI struct {
+	name  string
+	level Level
+	rand  *rand.Rand
+
+	settings   Settings
+	squares    []TrackSquare
+	currencies map[string]Currency
+	places     map[string]WorldPlace
+	dots       map[string]WorldDot
+	lucks      []LuckCard
+
+	// every ticket that can be bought, by where from
+	hops map[string][]aiHop
+	// cheapest cost between any two places
+	dist map[string]map[string]int
+
+	// the next place to go, once chosen
+	target string
+
+	// for not repeating moves that did not work
+	lastKey string
+	tried   map[string]bool
+}
+
+// NewAI makes an AI, to play as the named player.
+func NewAI(data GameData, name string, level Level, seed int64) *AI {
+	// copy the world, as linking changes it
+	places := map[string]WorldPlace{}
+	for id, p := range data.Places {
+		places[id] = p
+	}
+	dots := map[string]WorldDot{}
+	for id, d := range data.Dots {
+		d.Links = append([]string(nil), d.Links...)
+		dots[id] = d
+	}
+	linkWorld(places, dots)
+
+	a := &AI{
+		name:       name,
+		level:      level,
+		rand:       rand.New(rand.NewSource(seed)),
+		settings:   data.Settings,
+		squares:    data.Squares,
+		currencies: data.Currencies,
+		places:     places,
+		dots:       dots,
+		lucks:      data.Lucks,
+	}
+	a.planWorld()
+	return a
+}
+
+// planWorld works out every hop, and the cheapest way between every pair of
+// places, so that planning later is just looking things up.
+func (a *AI) planWorld() {
+	a.hops = map[string][]aiHop{}
+	for from, place := range a.places {
+		for key, price := range place.Routes {
+			ss := strings.SplitN(key, ":", 2)
+			to, modes := ss[0], ss[1]
+			if _, ok := a.places[to]; !ok {
+				continue
+			}
+			// only tickets that the game can actually route
+			r := route(a.dots, place.Dot, a.places[to].Dot, modes)
+			if len(r) < 2 {
+				continue
+			}
+			a.hops[from] = append(a.hops[from], aiHop{to, modes, price, len(r) - 1})
+		}
+	}
+
+	// Floyd-Warshall, which is fine for a few dozen places
+	a.dist = map[string]map[string]int{}
+	for p := range a.places {
+		a.dist[p] = map[string]int{p: 0}
+	}
+	for from, hops := range a.hops {
+		for _, h := range hops {
+			if d, ok := a.dist[from][h.to]; !ok || h.cost() < d {
+				a.dist[from][h.to] = h.cost()
+			}
+		}
+	}
+	for k := range a.places {
+		for i := range a.places {
+			ik, ok := a.dist[i][k]
+			if !ok {
+				continue
+			}
+			for j := range a.places {
+				kj, ok := a.dist[k][j]
+				if !ok {
+					continue
+				}
+				if ij, ok := a.dist[i][j]; !ok || ik+kj < ij {
+					a.dist[i][j] = ik + kj
+				}
+			}
+		}
+	}
+}
+
+func (a *AI) distance(from, to string) int {
+	if d, ok := a.dist[from][to]; ok {
+		return d
+	}
+	return math.MaxInt32
+}
+
+// aiView is what the AI can see of its turn.
+type aiView struct {
+	goal  int
+	me    PlayerState
+	place string
+	turn  TurnState
+	can   []string
+	must  []string
+}
+
+func (v aiView) allowed(cmd string) bool {
+	for _, l := range [][]string{v.can, v.must} {
+		for _, p := range l {
+			if game.CommandPattern(p).Match(game.CommandString(cmd)) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v aiView) canDo(prefix string) []string {
+	var out []string
+	for _, p := range v.can {
+		if game.CommandPattern(p).First() == prefix {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Play implements game.Bot.
+func (a *AI) Play(update game.GameUpdate) *game.Command {
+	if update.Status != game.StatusInProgress || update.Turn == nil {
+		return nil
+	}
+
+	v, ok := a.view(update)
+	if !ok {
+		return nil
+	}
+
+	var options []string
+	if a.level == LevelEasy && a.rand.Float64() < aiEasyWander {
+		options = a.randomMoves(v)
+	}
+	options = append(options, a.moves(v)...)
+	options = append(options, a.randomMoves(v)...)
+
+	// if this is the same as last time, the last moves didn't work
+	key := fmt.Sprint(update.Turn.Number, v.can, v.must, v.turn, v.me.Dot, v.me.Square, v.me.Money, v.me.Souvenirs, v.me.Lucks, v.me.Debts)
+	if key != a.lastKey {
+		a.lastKey = key
+		a.tried = map[string]bool{}
+	}
+
+	for _, cmd := range options {
+		if !a.tried[cmd] {
+			a.tried[cmd] = true
+			return &game.Command{Command: game.CommandString(cmd)}
+		}
+	}
+
+	// everything failed, so go round again, in case it was bad luck
+	if len(options) == 0 {
+		return nil
+	}
+	a.tried = map[string]bool{options[0]: true}
+	return &game.Command{Command: game.CommandString(options[0])}
+}
+
+func (a *AI) view(update game.GameUpdate) (aiView, bool) {
+	global := GlobalState{}
+	err := json.Unmarshal(update.Global, &global)
+	if err != nil {
+		return aiView{}, false
+	}
+	me, ok := global.Players[a.name]
+	if !ok {
+		return aiView{}, false
+	}
+
+	// custom is whatever the JSON decoder made of it, if anything
+	turn := TurnState{}
+	bs, _ := json.Marshal(update.Turn.Custom)
+	json.Unmarshal(bs, &turn)
+
+	goal := global.Goal
+	if goal == 0 {
+		goal = a.settings.Goal
+	}
+
+	return aiView{
+		goal:  goal,
+		me:    me,
+		place: a.dots[me.Dot].Place,
+		turn:  turn,
+		can:   update.Turn.Can,
+		must:  update.Turn.Must,
+	}, true
+}
+
+// moves lists what to do, best first.
+func (a *AI) moves(v aiView) []string {
+	var out []string
+	add := func(cmds ...string) {
+		for _, cmd := range cmds {
+			if cmd != "" && v.allowed(cmd) {
+				out = append(out, cmd)
+			}
+		}
+	}
+
+	a.updateTarget(v)
+
+	add(a.mustMoves(v)...)
+	add(a.debtMoves(v)...)
+	add(a.souvenirMoves(v)...)
+
+	if a.level >= LevelNormal {
+		add("insurance")
+		add(a.luckMoves(v)...)
+	}
+
+	if a.airliftHelps(v) {
+		add("airlift")
+	}
+
+	add("dicemove")
+
+	if !v.turn.Stopped {
+		if a.level >= LevelHard && !v.turn.OnMap {
+			add(a.advanceOnTrack(v))
+		}
+		add("stop")
+	}
+
+	// once stopped, get money first if it's needed for the ticket
+	add(a.changeMoves(v)...)
+	add(a.ticketMoves(v)...)
+
+	add("end")
+
+	return out
+}
+
+// randomMoves is anything that needs no arguments.
+func (a *AI) randomMoves(v aiView) []string {
+	var out []string
+	for _, l := range [][]string{v.must, v.can} {
+		for _, p := range l {
+			if !strings.Contains(p, "*") {
+				out = append(out, p)
+			}
+		}
+	}
+	a.rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// updateTarget chooses the next place to go, if the old one is done with.
+func (a *AI) updateTarget(v aiView) {
+	home := a.settings.Home
+
+	if len(v.me.Souvenirs) >= v.goal {
+		a.target = home
+		return
+	}
+
+	if a.target != "" && a.target != home && !stringListContains(v.me.Souvenirs, a.target) {
+		if a.level == LevelEasy || v.place != a.target || v.turn.OnMap {
+			// keep going
+			return
+		}
+	}
+
+	from := v.place
+	if from == "" && v.me.Ticket != nil {
+		from = v.me.Ticket.To
+	}
+
+	var wanted []string
+	for id, place := range a.places {
+		if place.Souvenir != "" && !stringListContains(v.me.Souvenirs, id) {
+			if _, ok := a.dist[from][id]; ok || from == "" {
+				wanted = append(wanted, id)
+			}
+		}
+	}
+	if len(wanted) == 0 {
+		a.target = home
+		return
+	}
+
+	need := v.goal - len(v.me.Souvenirs)
+
+	switch {
+	case a.level == LevelEasy:
+		a.target = wanted[a.rand.Intn(len(wanted))]
+	case a.level == LevelHard && need <= 3:
+		a.target = a.bestTour(from, wanted, need)
+	default:
+		a.target = a.nearest(from, wanted)
+	}
+}
+
+func (a *AI) nearest(from string, wanted []string) string {
+	best := ""
+	for _, p := range wanted {
+		if best == "" || a.distance(from, p) < a.distance(from, best) ||
+			(a.distance(from, p) == a.distance(from, best) && p < best) {
+			best = p
+		}
+	}
+	return best
+}
+
+// bestTour tries every order of visiting need places then going home, and
+// returns the first place of the cheapest.
+func (a *AI) bestTour(from string, wanted []string, need int) string {
+	home := a.settings.Home
+	bestCost := math.MaxInt32
+	bestFirst := ""
+
+	var try func(at string, cost int, left int, first string, used map[string]bool)
+	try = func(at string, cost int, left int, first string, used map[string]bool) {
+		if cost >= bestCost {
+			return
+		}
+		if left == 0 {
+			total := cost + a.distance(at, home)
+			if total < bestCost {
+				bestCost = total
+				bestFirst = first
+			}
+			return
+		}
+		for _, p := range wanted {
+			if used[p] {
+				continue
+			}
+			used[p] = true
+			f := first
+			if f == "" {
+				f = p
+			}
+			try(p, cost+a.distance(at, p), left-1, f, used)
+			used[p] = false
+		}
+	}
+	try(from, 0, need, "", map[string]bool{})
+
+	if bestFirst == "" {
+		return a.nearest(from, wanted)
+	}
+	return bestFirst
+}
+
+// bestHop is the best ticket from here towards the target, out of those that
+// match the modes, which can be "*" for any.
+func (a *AI) bestHop(from, modes string) (aiHop, bool) {
+	var best aiHop
+	found := false
+	bestCost := math.MaxInt32
+	here := a.distance(from, a.target)
+	for _, h := range a.hops[from] {
+		if modes != "*" && h.modes != modes {
+			continue
+		}
+		rest := a.distance(h.to, a.target)
+		if h.to != a.target && rest >= here {
+			// no progress
+			continue
+		}
+		if c := h.cost() + rest; c < bestCost {
+			best, bestCost, found = h, c, true
+		}
+	}
+	return best, found
+}
+
+func (a *AI) localPrice(place string, neutral int) (string, int) {
+	currency := a.places[place].Currency
+	return currency, neutral * a.currencies[currency].Rate / 100
+}
+
+func (a *AI) wantsSouvenir(v aiView, place string) bool {
+	if stringListContains(v.me.Souvenirs, place) {
+		return false
+	}
+	if a.level == LevelEasy {
+		return true
+	}
+	return place == a.target || len(v.me.Souvenirs) < v.goal
+}
+
+func (a *AI) mustMoves(v aiView) []string {
+	var out []string
+	for _, m := range v.must {
+		switch game.CommandPattern(m).First() {
+		case "quarantine":
+			out = append(out, a.useCard(v, func(c LuckCode) bool { _, ok := c.(LuckInoculation); return ok }))
+			out = append(out, m)
+		case "paycustoms":
+			out = append(out, a.useCard(v, func(c LuckCode) bool { _, ok := c.(LuckImmunity); return ok }))
+			out = append(out, m)
+		case "declare":
+			out = append(out, a.useCard(v, func(c LuckCode) bool { _, ok := c.(LuckImmunity); return ok }))
+			if n := len(v.me.Souvenirs); n > 0 {
+				// the one furthest from where we are going matters least
+				out = append(out, "declare:"+v.me.Souvenirs[n-1])
+			} else {
+				out = append(out, "declare:none")
+			}
+		case "obeyrisk":
+			id := strings.TrimPrefix(m, "obeyrisk:")
+			out = append(out, "ignorerisk:"+id, m)
+		default:
+			if !strings.Contains(m, "*") {
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+// useCard finds a held luck card that matches, if the AI plays cards.
+func (a *AI) useCard(v aiView, match func(LuckCode) bool) string {
+	if a.level < LevelNormal {
+		return ""
+	}
+	for _, id := range v.me.Lucks {
+		if id < len(a.lucks) && match(a.lucks[id].ParseCode()) {
+			return "useluck:" + strconv.Itoa(id)
+		}
+	}
+	return ""
+}
+
+func (a *AI) debtMoves(v aiView) []string {
+	var out []string
+	for _, debt := range v.me.Debts {
+		currencies := []string{debt.Currency}
+		if debt.Currency == "*" {
+			currencies = a.currenciesByValue(v.me.Money)
+			if v.place != "" {
+				// local money is the most use, as it can be changed back
+				currencies = append([]string{a.places[v.place].Currency}, currencies...)
+			}
+		}
+		for _, cid := range currencies {
+			c, ok := a.currencies[cid]
+			if !ok {
+				continue
+			}
+			amount := roundUp(debt.Amount*c.Rate/100, c.Units[0])
+			if amount > 0 && v.me.Money[cid] >= amount {
+				out = append(out, fmt.Sprintf("pay:%s:%d", cid, amount))
+				break
+			}
+		}
+	}
+	return out
+}
+
+func (a *AI) souvenirMoves(v aiView) []string {
+	var out []string
+	for _, p := range v.canDo("buysouvenir") {
+		place := strings.TrimPrefix(p, "buysouvenir:")
+		if !a.wantsSouvenir(v, place) {
+			continue
+		}
+		currency, price := a.localPrice(place, a.settings.SouvenirPrice)
+		if v.me.Money[currency] >= price {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (a *AI) airliftHelps(v aiView) bool {
+	if v.me.Ticket != nil || v.place == "" || a.level == LevelEasy {
+		return false
+	}
+	return a.distance("capetown", a.target) < a.distance(v.place, a.target)
+}
+
+func (a *AI) luckMoves(v aiView) []string {
+	var out []string
+	for _, id := range v.me.Lucks {
+		if id >= len(a.lucks) {
+			continue
+		}
+		use := "useluck:" + strconv.Itoa(id)
+		switch code := a.lucks[id].ParseCode().(type) {
+		case LuckDest:
+			if v.turn.OnMap && !v.turn.Stopped && v.me.Ticket != nil {
+				out = append(out, use)
+			}
+		case LuckFreeTicket:
+			if v.turn.OnMap || v.me.Ticket != nil || v.place == "" {
+				continue
+			}
+			if code.From != "*" && code.From != v.place {
+				continue
+			}
+			h, ok := a.bestHop(v.place, code.Modes)
+			if ok && (code.To == "*" || code.To == h.to) {
+				out = append(out, fmt.Sprintf("%s:%s:%s:%s", use, v.place, h.to, h.modes))
+			}
+		case LuckAdvance:
+			if !v.turn.OnMap || v.turn.Stopped || v.me.Ticket == nil || stringListContains(v.can, "dicemove") {
+				continue
+			}
+			left := a.dotsLeft(v)
+			if left == code.N || (a.level >= LevelHard && code.N < left) {
+				out = append(out, use)
+			}
+		}
+	}
+	return out
+}
+
+// dotsLeft is how far there is to go on the current ticket.
+func (a *AI) dotsLeft(v aiView) int {
+	t := v.me.Ticket
+	r := route(a.dots, a.places[t.From].Dot, a.places[t.To].Dot, t.By)
+	for i, d := range r {
+		if d == v.me.Dot {
+			return len(r) - 1 - i
+		}
+	}
+	return 0
+}
+
+// advanceOnTrack uses an advance card, if it gets to a better square.
+func (a *AI) advanceOnTrack(v aiView) string {
+	if stringListContains(v.can, "dicemove") {
+		// not moved yet
+		return ""
+	}
+	here := a.squareScore(v, v.me.Square)
+	best, bestScore := "", here
+	for _, id := range v.me.Lucks {
+		if id >= len(a.lucks) {
+			continue
+		}
+		if code, ok := a.lucks[id].ParseCode().(LuckAdvance); ok {
+			sq := (v.me.Square + code.N) % len(a.squares)
+			if score := a.squareScore(v, sq); score > bestScore {
+				best, bestScore = "useluck:"+strconv.Itoa(id), score
+			}
+		}
+	}
+	return best
+}
+
+// squareScore is how good a square on the track would be to stop on.
+func (a *AI) squareScore(v aiView, n int) int {
+	square := a.squares[n]
+	needTicket := v.me.Ticket == nil && v.place != "" && v.place != a.target
+	score := 0
+	switch square.Type {
+	case "customs1", "hotel":
+		score -= 1
+	case "customs2":
+		if len(v.me.Souvenirs) > 0 {
+			score -= 3
+		}
+	case "hospital", "quarantine", "badpapers":
+		score -= 2
+	case "luck", "curspec":
+		score += 1
+	case "gocooks":
+		if needTicket {
+			score += 3
+		}
+	}
+	for _, o := range square.ParseOptions() {
+		if can, ok := o.(OptionCan); ok {
+			parts := can.Cmd.Parts()
+			switch parts[0] {
+			case "buyticket":
+				if needTicket {
+					if _, ok := a.bestHop(v.place, parts[3]); ok {
+						score += 3
+					}
+				}
+			case "changemoney":
+				if a.moneyShort(v) > 0 {
+					score += 2
+				}
+			}
+		}
+	}
+	return score
+}
+
+// moneyShort is how much more local money is wanted, for the next ticket, a
+// souvenir, and something to spare.
+func (a *AI) moneyShort(v aiView) int {
+	if v.place == "" {
+		return 0
+	}
+	currency := a.places[v.place].Currency
+	need := 0
+	if v.me.Ticket == nil && v.place != a.target {
+		if h, ok := a.bestHop(v.place, "*"); ok {
+			_, fare := a.localPrice(v.place, h.price)
+			need += fare
+		}
+	}
+	if a.places[v.place].Souvenir != "" && a.wantsSouvenir(v, v.place) {
+		_, price := a.localPrice(v.place, a.settings.SouvenirPrice)
+		need += price
+	}
+	if a.level >= LevelNormal {
+		_, reserve := a.localPrice(v.place, aiReserve)
+		need += reserve
+	}
+	return need - v.me.Money[currency]
+}
+
+func (a *AI) changeMoves(v aiView) []string {
+	changes := v.canDo("changemoney")
+	if len(changes) == 0 || v.place == "" {
+		return nil
+	}
+	short := a.moneyShort(v)
+	if short <= 0 {
+		return nil
+	}
+
+	to := a.places[v.place].Currency
+	toRate := a.currencies[to].Rate
+
+	var out []string
+	for _, from := range a.currenciesByValue(v.me.Money) {
+		if from == to {
+			continue
+		}
+		c := a.currencies[from]
+		unit := c.Units[0]
+		amount := roundUp(short*c.Rate/toRate, unit)
+		if have := v.me.Money[from] / unit * unit; amount > have {
+			amount = have
+		}
+		if amount > 0 {
+			out = append(out, fmt.Sprintf("changemoney:%s:%s:%d", from, to, amount))
+		}
+	}
+	return out
+}
+
+func (a *AI) ticketMoves(v aiView) []string {
+	if v.me.Ticket != nil || v.place == "" || v.place == a.target {
+		return nil
+	}
+	var out []string
+	for _, p := range v.canDo("buyticket") {
+		parts := game.CommandPattern(p).Parts()
+		h, ok := a.bestHop(v.place, parts[3])
+		if !ok {
+			continue
+		}
+		currency, fare := a.localPrice(v.place, h.price)
+		if v.me.Money[currency] < fare {
+			continue
+		}
+		out = append(out, fmt.Sprintf("buyticket:%s:%s:%s", v.place, h.to, h.modes))
+	}
+	return out
+}
+
+// currenciesByValue lists held currencies, most valuable holding first.
+func (a *AI) currenciesByValue(money map[string]int) []string {
+	var out []string
+	for id, n := range money {
+		if n > 0 {
+			out = append(out, id)
+		}
+	}
+	value := func(id string) int {
+		return money[id] * 100 / a.currencies[id].Rate
+	}
+	// few enough for a simple sort
+	for i := range out {
+		for j := i + 1; j < len(out); j++ {
+			if value(out[j]) > value(out[i]) || (value(out[j]) == value(out[i]) && out[j] < out[i]) {
+				out[i], out[j] = out[j], out[i]
+			}
+		}
+	}
+	return out
+}
+
+func roundUp(n, unit int) int {
+	if unit <= 0 {
+		return n
+	}
+	return (n + unit - 1) / unit * unit
+}
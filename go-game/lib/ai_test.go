@@ -0,0 +1,112 @@
+package gogame
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// playAIs plays a whole game between AIs, returning the winner, if any, and
+// how many turns were played.
+func playAIs(t *testing.T, levels map[string]Level, maxCommands int) (string, int) {
+	data := LoadJson("..")
+	g := NewGame(data, 2)
+
+	colours := []string{"red", "blue", "green", "yellow"}
+	ais := map[string]*AI{}
+	i := 0
+	for name, level := range levels {
+		err := g.AddPlayer(name, map[string]interface{}{"colour": colours[i]})
+		if err != nil {
+			t.Fatalf("add player error: %v", err)
+		}
+		ais[name] = NewAI(data, name, level, int64(i))
+		i++
+	}
+
+	err := g.Start()
+	if err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+
+	for n := 0; n < maxCommands; n++ {
+		state := g.GetGameState()
+		if state.Status == game.StatusWon {
+			return state.Winner, state.TurnNumber
+		}
+
+		global, _ := json.Marshal(state.Global)
+		update := game.GameUpdate{
+			Status:  state.Status,
+			Playing: state.Playing,
+			Global:  global,
+		}
+		for _, pl := range state.Players {
+			if pl.Name == state.Playing {
+				update.Turn = pl.Turn
+			}
+		}
+
+		cmd := ais[state.Playing].Play(update)
+		if cmd == nil {
+			t.Fatalf("%s is stuck, can %v, must %v", state.Playing, update.Turn.Can, update.Turn.Must)
+		}
+		_, err := g.Play(state.Playing, *cmd)
+		if err != nil {
+			// the AI notices, and tries something else
+			continue
+		}
+	}
+
+	return "", g.GetGameState().TurnNumber
+}
+
+func TestAI_plays(t *testing.T) {
+	for _, level := range []Level{LevelEasy, LevelNormal, LevelHard} {
+		winner, turns := playAIs(t, map[string]Level{"a": level, "b": level}, 20000)
+		if winner != "a" && winner != "b" {
+			t.Errorf("level %d: no winner after %d turns", level, turns)
+		}
+		if turns < 2 {
+			t.Errorf("level %d: game over too soon, after %d turns", level, turns)
+		}
+	}
+}
+
+func TestAI_hardBeatsEasy(t *testing.T) {
+	wins := map[string]int{}
+	// games are random, but enough of them should show it
+	for i := 0; i < 40; i++ {
+		winner, _ := playAIs(t, map[string]Level{"easy": LevelEasy, "hard": LevelHard}, 20000)
+		wins[winner]++
+	}
+	if wins["hard"] <= wins["easy"] {
+		t.Errorf("hard should usually win: %v", wins)
+	}
+}
+
+func TestAI_bestHop(t *testing.T) {
+	data := LoadJson("..")
+	ai := NewAI(data, "a", LevelNormal, 0)
+
+	ai.target = "paris"
+	h, ok := ai.bestHop("london", "*")
+	if !ok || h.to != "paris" || h.modes != "a" {
+		t.Errorf("bad hop: %v", h)
+	}
+
+	// no cars from london
+	if _, ok := ai.bestHop("london", "l"); ok {
+		t.Errorf("should be no way by car")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if l, err := ParseLevel("hard"); err != nil || l != LevelHard {
+		t.Errorf("bad level: %v %v", l, err)
+	}
+	if _, err := ParseLevel("silly"); err == nil {
+		t.Errorf("expected error")
+	}
+}
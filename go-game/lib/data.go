@@ -2,12 +2,18 @@ package gogame
 
 import (
 	"encoding/json"
-	"io/ioutil"
-	"path"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/undeconstructed/gogogo/game"
 )
 
 // GlobalState is the info that can be seen by all players
 type GlobalState struct {
+	Goal    int                    `json:"goal"`
 	Players map[string]PlayerState `json:"players"`
 }
 
@@ -35,22 +41,50 @@ type TurnState struct {
 
 // LoadJson loads the GameData from a file.
 func LoadJson(dir string) GameData {
-	fileName := path.Join(dir, "data.json")
-	jsdata, err := ioutil.ReadFile(fileName)
+	data, err := ReadJson(dir)
+	if err != nil {
+		panic(err.Error())
+	}
+	return data
+}
+
+// LoadJsonFS loads the GameData from data.json in some files.
+func LoadJsonFS(fsys fs.FS) GameData {
+	data, err := ReadJsonFS(fsys)
 	if err != nil {
-		panic("no data.json")
+		panic(err.Error())
+	}
+	return data
+}
+
+// ReadJson loads the GameData from a file, for when a panic won't do.
+func ReadJson(dir string) (GameData, error) {
+	return ReadJsonFS(os.DirFS(dir))
+}
+
+// ReadJsonFS loads the GameData from data.json in some files, e.g. the
+// embedded assets.
+func ReadJsonFS(fsys fs.FS) (GameData, error) {
+	jsdata, err := fs.ReadFile(fsys, "data.json")
+	if err != nil {
+		return GameData{}, errors.New("no data.json")
 	}
 	var data GameData
 	err = json.Unmarshal(jsdata, &data)
 	if err != nil {
-		panic("bad data.json: " + err.Error())
+		return GameData{}, errors.New("bad data.json: " + err.Error())
 	}
-	return data
+	data.Messages, err = game.ReadCatalogue(fsys, "en")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return GameData{}, err
+	}
+	return data, nil
 }
 
 // GameData is the JSON structure of the game data.
 type GameData struct {
 	Settings   Settings              `json:"settings"`
+	Modes      map[string]string     `json:"modes"`
 	Actions    map[string]Action     `json:"actions"`
 	Squares    []TrackSquare         `json:"squares"`
 	Currencies map[string]Currency   `json:"currencies"`
@@ -58,6 +92,51 @@ type GameData struct {
 	Dots       map[string]WorldDot   `json:"dots"`
 	Lucks      []LuckCard            `json:"lucks"`
 	Risks      []RiskCard            `json:"risks"`
+
+	// Messages are the English descriptions of news, from messages/en.json
+	Messages game.Catalogue `json:"-"`
+}
+
+// Name is a game.Namer, for the things in news.
+func (data GameData) Name(kind, id string) string {
+	switch kind {
+	case "place":
+		if p, ok := data.Places[id]; ok {
+			return p.Name
+		}
+	case "souvenir":
+		if p, ok := data.Places[id]; ok {
+			return p.Souvenir
+		}
+	case "currency":
+		if c, ok := data.Currencies[id]; ok {
+			return c.Name
+		}
+	case "modes":
+		var names []string
+		for _, m := range id {
+			if name, ok := data.Modes[string(m)]; ok {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			return strings.Join(names, "/")
+		}
+	case "square", "luck", "risk":
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			break
+		}
+		switch {
+		case kind == "square" && n >= 0 && n < len(data.Squares):
+			return data.Squares[n].Name
+		case kind == "luck" && n >= 0 && n < len(data.Lucks):
+			return data.Lucks[n].Name
+		case kind == "risk" && n >= 0 && n < len(data.Risks):
+			return data.Risks[n].Name
+		}
+	}
+	return id
 }
 
 // Settings is things that control the game, and may be overriden per game.
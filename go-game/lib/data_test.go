@@ -0,0 +1,36 @@
+package gogame
+
+import (
+	"os"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestMessages(t *testing.T) {
+	data := LoadJson("..")
+	de, err := game.ReadCatalogue(os.DirFS(".."), "de")
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	// every language has every message
+	for code := range data.Messages {
+		if _, ok := de[code]; !ok {
+			t.Errorf("de has no message for %s", code)
+		}
+	}
+	for code := range de {
+		if _, ok := data.Messages[code]; !ok {
+			t.Errorf("en has no message for %s", code)
+		}
+	}
+
+	ch := game.Change{Code: "buyticket", Params: game.Params{"to": "bombay", "modes": "sa", "fare": 40, "currency": "ru"}}
+	if what := data.Messages.Describe(ch, data.Name); what != "buys a ticket to Bombay by Sea/Air for 40 Rupees" {
+		t.Errorf("bad en: %q", what)
+	}
+	if what := de.Describe(ch, data.Name); what != "kauft eine Fahrkarte nach Bombay per Sea/Air für 40 Rupees" {
+		t.Errorf("bad de: %q", what)
+	}
+}
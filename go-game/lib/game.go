@@ -23,6 +23,8 @@ type gogame struct {
 	dots       map[string]WorldDot
 	risks      []RiskCard
 	lucks      []LuckCard
+	messages   game.Catalogue
+	names      game.Namer
 
 	riskPile CardStack
 	luckPile CardStack
@@ -34,6 +36,52 @@ type gogame struct {
 	winner  string
 }
 
+// Description describes the game, with schemas for the options that NewGame
+// and AddPlayer understand.
+func Description(data GameData) game.Description {
+	souvenirs := 0
+	for _, p := range data.Places {
+		if p.Souvenir != "" {
+			souvenirs++
+		}
+	}
+
+	options, _ := json.Marshal(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"goal": map[string]interface{}{
+				"type":        "integer",
+				"description": "souvenirs to collect before going home",
+				"minimum":     1,
+				"maximum":     souvenirs,
+			},
+		},
+		"additionalProperties": false,
+	})
+	playerOptions, _ := json.Marshal(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"colour": map[string]interface{}{
+				"type": "string",
+				"enum": colours,
+			},
+		},
+		"required":             []string{"colour"},
+		"additionalProperties": false,
+	})
+
+	return game.Description{
+		Name:          "go",
+		DisplayName:   "Go",
+		MinPlayers:    1,
+		MaxPlayers:    6,
+		Options:       options,
+		PlayerOptions: playerOptions,
+		Version:       "1",
+		Bots:          []string{"easy", "normal", "hard"},
+	}
+}
+
 func NewGame(data GameData, goal int) game.Game {
 	g := &gogame{}
 
@@ -72,6 +120,8 @@ func NewGame(data GameData, goal int) game.Game {
 	g.settings.Goal = goal
 
 	// import data
+	g.messages = data.Messages
+	g.names = data.Name
 	g.squares = data.Squares
 	g.currencies = data.Currencies
 	g.places = data.Places
@@ -79,44 +129,7 @@ func NewGame(data GameData, goal int) game.Game {
 	g.lucks = data.Lucks
 	g.risks = data.Risks
 
-	// link places to dots
-
-	for p, d := range g.dots {
-		if d.Place != "" {
-			pl := g.places[d.Place]
-			pl.Dot = p
-			g.places[d.Place] = pl
-			if pl.City {
-				// cities are terminal
-				d.Terminal = true
-				g.dots[p] = d
-			}
-		}
-	}
-
-	// make links 2-way
-
-	appendIfMissing := func(list []string, item string) []string {
-		for _, i := range list {
-			if i == item {
-				return list
-			}
-		}
-		return append(list, item)
-	}
-	for p, d := range g.dots {
-		for _, l := range d.Links {
-			mode := l[0]
-			tgtp := l[2:]
-			rlink := string(mode) + ":" + p
-			tgtd, ok := g.dots[tgtp]
-			if !ok {
-				panic("bad link " + l)
-			}
-			tgtd.Links = appendIfMissing(tgtd.Links, rlink)
-			g.dots[tgtp] = tgtd
-		}
-	}
+	linkWorld(g.places, g.dots)
 
 	// set up bank
 
@@ -171,6 +184,49 @@ func NewGame(data GameData, goal int) game.Game {
 	return g
 }
 
+// linkWorld links places to their dots, and makes all the links between dots
+// go both ways.
+func linkWorld(places map[string]WorldPlace, dots map[string]WorldDot) {
+	// link places to dots
+
+	for p, d := range dots {
+		if d.Place != "" {
+			pl := places[d.Place]
+			pl.Dot = p
+			places[d.Place] = pl
+			if pl.City {
+				// cities are terminal
+				d.Terminal = true
+				dots[p] = d
+			}
+		}
+	}
+
+	// make links 2-way
+
+	appendIfMissing := func(list []string, item string) []string {
+		for _, i := range list {
+			if i == item {
+				return list
+			}
+		}
+		return append(list, item)
+	}
+	for p, d := range dots {
+		for _, l := range d.Links {
+			mode := l[0]
+			tgtp := l[2:]
+			rlink := string(mode) + ":" + p
+			tgtd, ok := dots[tgtp]
+			if !ok {
+				panic("bad link " + l)
+			}
+			tgtd.Links = appendIfMissing(tgtd.Links, rlink)
+			dots[tgtp] = tgtd
+		}
+	}
+}
+
 func NewFromSaved(data GameData, r io.Reader) (game.Game, error) {
 	// do default setup
 	g := NewGame(data, 5).(*gogame)
@@ -247,7 +303,7 @@ func (g *gogame) AddPlayer(name string, options map[string]interface{}) error {
 // Start starts the game
 func (g *gogame) Start() error {
 	if g.turn != nil {
-		return game.Error(game.StatusNotStarted, "")
+		return game.Error(game.StatusAlreadyStarted, "")
 	}
 	if len(g.players) < 1 {
 		return game.Error(game.StatusNoPlayers, "")
@@ -284,6 +340,12 @@ func (g *gogame) Play(player string, c game.Command) (game.PlayResult, error) {
 
 	news := t.news
 	t.news = nil
+	for i := range news {
+		news[i].What = g.messages.Describe(news[i], g.names)
+		if news[i].What == "" {
+			news[i].What = news[i].Code
+		}
+	}
 
 	return game.PlayResult{Response: res, News: news}, nil
 }
@@ -350,6 +412,7 @@ func (g *gogame) GetGameState() game.GameState {
 	}
 
 	var global = GlobalState{
+		Goal:    g.settings.Goal,
 		Players: map[string]PlayerState{},
 	}
 	var players []game.PlayerState
@@ -458,15 +521,13 @@ func (g *gogame) moveOnTrack(t *turn, n int) {
 	}
 	t.player.OnSquare = tp1
 
-	square := g.squares[t.player.OnSquare]
-
-	t.addEventf("walks %d squares to %s", n, square.Name)
+	t.addEvent("walk", game.Params{"n": n, "square": t.player.OnSquare})
 }
 
 func (g *gogame) passGo(t *turn) {
 	// XXX - unhardcode
 	g.moveMoney(g.bank.Money, t.player.Money, "tc", 200)
-	t.addEvent("passes go")
+	t.addEvent("passgo", nil)
 }
 
 func (g *gogame) makeSubs(t *turn) map[string]string {
@@ -495,7 +556,7 @@ func (g *gogame) stopOnTrack(t *turn) {
 	t.Stopped = true
 
 	square := g.squares[t.player.OnSquare]
-	t.addEventf("goes into %s", square.Name)
+	t.addEvent("stoptrack", game.Params{"square": t.player.OnSquare})
 
 	for _, o := range square.ParseOptions() {
 		switch option := o.(type) {
@@ -511,11 +572,11 @@ func (g *gogame) stopOnTrack(t *turn) {
 		case OptionGo:
 			g.jumpOnTrack(t, option.Dest, option.Forwards)
 			// recurse, to get effects of the new location
-			t.addEventf("jumps to %s", option.Dest)
+			t.addEvent("jump", game.Params{"square": t.player.OnSquare})
 			g.stopOnTrack(t)
 		case OptionMiss:
 			t.player.MissTurns += option.N
-			t.addEventf("will miss %d turns", option.N)
+			t.addEvent("miss", game.Params{"n": option.N})
 		case OptionMust:
 			cmd := option.Cmd.Sub(g.makeSubs(t))
 			t.Must = append(t.Must, string(cmd))
@@ -588,28 +649,28 @@ func (g *gogame) moveAlongRoute(t *turn, n int) bool {
 	need := len(toGo)
 	if n > need {
 		// overshot
-		t.addEventf("tries to move %d, but overshoots", n)
+		t.addEvent("overshoot", game.Params{"n": n})
 		return false
 	} else if n == need {
 		// reached
 		t.player.OnDot = toGo[need-1]
 		t.player.Ticket = nil
 		t.Moved = true
-		t.addEventf("moves %d and arrives", n)
+		t.addEvent("arrive", game.Params{"n": n})
 		return true
 	} else {
 		wouldDot := toGo[n-1]
 		isFree := g.dotIsFree(wouldDot)
 		if !isFree {
 			t.Moved = true
-			t.addEventf("tries to move %d, but someone else is there", n)
+			t.addEvent("blocked", game.Params{"n": n})
 			return false
 		}
 
 		t.player.OnDot = wouldDot
 		t.Moved = true
 
-		t.addEventf("moves %d", n)
+		t.addEvent("move", game.Params{"n": n})
 		return false
 	}
 }
@@ -623,7 +684,7 @@ func (g *gogame) jumpOnMap(t *turn, destPlace string) {
 func (g *gogame) stopOnMap(t *turn) {
 	t.Stopped = true
 
-	t.addEvent("stops moving")
+	t.addEvent("stop", nil)
 
 	if t.Moved {
 		t.Can, _ = stringListWithout(t.Can, "dicemove")
@@ -645,7 +706,7 @@ func (g *gogame) stopOnMap(t *turn) {
 			t.player.Insurance = false
 
 			if g.settings.Home == placeId && len(t.player.Souvenirs) >= g.settings.Goal && g.winner == "" {
-				t.addEvent("wins the game!")
+				t.addEvent("win", nil)
 				g.winner = t.player.Name
 			}
 		}
@@ -840,13 +901,10 @@ type turn struct {
 	news []game.Change
 }
 
-func (t *turn) addEvent(msg string) {
-	t.news = append(t.news, game.Change{Who: t.player.Name, What: msg, Where: t.player.OnDot})
-}
-
-func (t *turn) addEventf(format string, a ...interface{}) {
-	msg := fmt.Sprintf(format, a...)
-	t.news = append(t.news, game.Change{Who: t.player.Name, What: msg, Where: t.player.OnDot})
+// addEvent records something that happened, by code. The words are added at
+// the end, from the messages.
+func (t *turn) addEvent(code string, params game.Params) {
+	t.news = append(t.news, game.Change{Who: t.player.Name, Where: t.player.OnDot, Code: code, Params: params})
 }
 
 type bank struct {
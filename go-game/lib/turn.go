@@ -17,7 +17,7 @@ func (g *gogame) turn_airlift(t *turn, c game.CommandPattern, args []string) (in
 
 	t.Can, _ = stringListWithout(t.Can, string(c))
 
-	t.addEvent("suddenly appears")
+	t.addEvent("appear", nil)
 	return nil, nil
 }
 
@@ -54,7 +54,7 @@ func (g *gogame) turn_buysouvenir(t *turn, c game.CommandPattern, args []string)
 	t.player.HasBought = true
 	t.Can, _ = stringListWithout(t.Can, string(c))
 
-	t.addEventf("buys a souvenir %s", place.Souvenir)
+	t.addEvent("buysouvenir", game.Params{"place": placeId})
 	return place.Souvenir, nil
 }
 
@@ -86,7 +86,7 @@ func (g *gogame) turn_buyticket(t *turn, c game.CommandPattern, args []string) (
 	g.moveMoney(t.player.Money, g.bank.Money, ticket.Currency, ticket.Fare)
 	t.player.Ticket = &ticket
 
-	t.addEventf("buys a ticket to %s by %s for %d %s", to, modes, ticket.Fare, ticket.Currency)
+	t.addEvent("buyticket", game.Params{"to": to, "modes": modes, "fare": ticket.Fare, "currency": ticket.Currency})
 	return nil, nil
 }
 
@@ -122,7 +122,7 @@ func (g *gogame) turn_changemoney(t *turn, c game.CommandPattern, args []string)
 	g.moveMoney(t.player.Money, g.bank.Money, from, amount)
 	g.moveMoney(g.bank.Money, t.player.Money, to, toAmount)
 
-	t.addEventf("changes %d %s into %d %s", amount, fromCurrency.Name, toAmount, toCurrency.Name)
+	t.addEvent("changemoney", game.Params{"amount": amount, "from": from, "toAmount": toAmount, "to": to})
 	return nil, nil
 }
 
@@ -134,7 +134,7 @@ func (g *gogame) turn_debt(t *turn, c game.CommandPattern, args []string) (inter
 	t.player.Debts = append(t.player.Debts, Debt{reason, amount, currency})
 	t.Can = append(t.Can, "pay:*:*")
 
-	t.addEventf("now owes %s %d for %s", currency, amount, reason)
+	t.addEvent("debt", game.Params{"amount": amount, "currency": currency, "reason": reason})
 
 	return nil, nil
 }
@@ -147,7 +147,7 @@ func (g *gogame) turn_declare(t *turn, c game.CommandPattern, args []string) (in
 			return nil, game.Error(game.StatusNotNow, "you have a souvenir, you must declare it")
 		}
 		t.Must, _ = stringListWithout(t.Must, string(c))
-		t.addEvent("declares no souvenirs")
+		t.addEvent("declarenone", nil)
 		return nil, nil
 	}
 
@@ -160,7 +160,7 @@ func (g *gogame) turn_declare(t *turn, c game.CommandPattern, args []string) (in
 	g.bank.Souvenirs[place]++
 	t.Must, _ = stringListWithout(t.Must, string(c))
 
-	t.addEventf("loses a souvenir from %s", place)
+	t.addEvent("declare", game.Params{"place": place})
 	return nil, nil
 }
 
@@ -207,11 +207,11 @@ func (g *gogame) turn_gamble(t *turn, c game.CommandPattern, args []string) (int
 
 	if roll >= 4 {
 		g.moveMoney(g.bank.Money, t.player.Money, currencyId, amount)
-		t.addEventf("gambles, rolls %d, and wins!", roll)
+		t.addEvent("gamblewin", game.Params{"roll": roll})
 		return "won", nil
 	} else {
 		g.moveMoney(t.player.Money, g.bank.Money, currencyId, amount)
-		t.addEventf("gambles, rolls %d, and loses!", roll)
+		t.addEvent("gamblelose", game.Params{"roll": roll})
 		return "lost", nil
 	}
 }
@@ -226,7 +226,7 @@ func (g *gogame) turn_getmoney(t *turn, c game.CommandPattern, args []string) (i
 
 	t.Can, _ = stringListWithout(t.Can, string(c))
 
-	t.addEventf("just finds %d %s", amount, currency.Name)
+	t.addEvent("getmoney", game.Params{"amount": amount, "currency": currencyId})
 	return nil, nil
 }
 
@@ -237,7 +237,7 @@ func (g *gogame) turn_insurance(t *turn, c game.CommandPattern, args []string) (
 
 	t.Can, _ = stringListWithout(t.Can, string(c))
 
-	t.addEventf("acquires an insurance policy")
+	t.addEvent("insurance", nil)
 	return nil, nil
 }
 
@@ -281,7 +281,7 @@ func (g *gogame) turn_obeyrisk(t *turn, c game.CommandPattern, args []string) (i
 		g.jumpOnMap(t, dest)
 		g.stopOnMap(t)
 
-		t.addEvent("arrives early")
+		t.addEvent("arriveearly", nil)
 	case RiskFog:
 		// "All transport - Fog. - Planes return to point of departure. - No new ticket required. - Ships and cars miss one turn. - Trains unaffected."
 		modes := t.player.Ticket.Mode
@@ -290,7 +290,7 @@ func (g *gogame) turn_obeyrisk(t *turn, c game.CommandPattern, args []string) (i
 			dest := t.player.Ticket.From
 			g.jumpOnMap(t, dest)
 			g.stopOnMap(t)
-			t.addEvent("is back")
+			t.addEvent("back", nil)
 		case strings.Contains(modes, "s"):
 			fallthrough
 		case strings.Contains(modes, "l"):
@@ -301,12 +301,11 @@ func (g *gogame) turn_obeyrisk(t *turn, c game.CommandPattern, args []string) (i
 		g.jumpOnMap(t, code.Dest)
 		g.stopOnMap(t)
 
-		t.addEvent("suddenly appears")
+		t.addEvent("appear", nil)
 	case RiskLoseTicket:
 		// XXX - have to work out how to get a new one ..
 		// g.loseTicket(t, true)
-		// t.addEvent("loses his ticket")
-		t.addEvent("thought that he'd lost his ticket")
+		t.addEvent("loseticket", nil)
 	case RiskMiss:
 		t.player.MissTurns += code.N
 	case RiskMust:
@@ -321,12 +320,12 @@ func (g *gogame) turn_obeyrisk(t *turn, c game.CommandPattern, args []string) (i
 		g.stopOnMap(t)
 
 		if code.LoseTicket {
-			t.addEvent("is back, ticketless")
+			t.addEvent("backticketless", nil)
 		} else {
-			t.addEvent("is back")
+			t.addEvent("back", nil)
 		}
 	case RiskCode:
-		t.addEvent("finds out that his risk card is unimplemented")
+		t.addEvent("riskunimplemented", nil)
 	default:
 		panic("bad risk card " + card.Code)
 	}
@@ -343,7 +342,7 @@ func (g *gogame) turn_pawnsouvenir(t *turn, c game.CommandPattern, args []string
 		return nil, game.Error(game.StatusNotNow, "souvenir not found")
 	}
 
-	t.addEventf("tries to pawn a souvenir from %s", place)
+	t.addEvent("pawnsouvenir", game.Params{"place": place})
 
 	return "not implemented", nil
 }
@@ -396,13 +395,13 @@ func (g *gogame) turn_pay(t *turn, c game.CommandPattern, args []string) (interf
 				cAmount := debt.Amount * currency.Rate / 100
 				// TODO - error checks
 				_ = g.moveMoney(t.player.Money, g.bank.Money, currencyId, cAmount)
-				t.addEventf("pays his %s debt", debt.Reason)
+				t.addEvent("paydebt", game.Params{"reason": debt.Reason})
 				nAmount -= debt.Amount
 			} else {
 				// can pay part
 				debt.Amount -= nAmount
 				nAmount = 0
-				t.addEventf("pays some of his %s debt", debt.Reason)
+				t.addEvent("paydebtpart", game.Params{"reason": debt.Reason})
 				newDebts = append(newDebts, debt)
 			}
 		} else {
@@ -430,7 +429,7 @@ func (g *gogame) turn_paycustoms(t *turn, c game.CommandPattern, args []string)
 		log.Error().Err(err).Msgf("auto command error: %s", cmd)
 	}
 
-	t.addEvent("agrees to pay his customs duty")
+	t.addEvent("paycustoms", nil)
 
 	return nil, nil
 }
@@ -440,7 +439,7 @@ func (g *gogame) turn_quarantine(t *turn, c game.CommandPattern, args []string)
 
 	t.Must, _ = stringListWithout(t.Must, string(c))
 
-	t.addEvent("enters quarantine")
+	t.addEvent("quarantine", nil)
 
 	return g.doAutoCommand(t, game.CommandPattern("end"))
 }
@@ -456,7 +455,7 @@ func (g *gogame) turn_sellsouvenir(t *turn, c game.CommandPattern, args []string
 		return nil, game.Error(game.StatusNotNow, "souvenir not found")
 	}
 
-	t.addEventf("tries to sell a souvenir from %s", place)
+	t.addEvent("sellsouvenir", game.Params{"place": place})
 
 	return "not implemented", nil
 }
@@ -480,13 +479,13 @@ func (g *gogame) turn_takeluck(t *turn, c game.CommandPattern, args []string) (i
 
 	cardId, pile := g.luckPile.Take()
 	if cardId < 0 {
-		t.addEvent("finds no luck cards")
+		t.addEvent("noluck", nil)
 		return nil, nil
 	}
 	g.luckPile = pile
 
 	card := g.lucks[cardId]
-	t.addEventf("gets a luck card: %s", card.Name)
+	t.addEvent("takeluck", game.Params{"card": cardId})
 	if card.Retain {
 		t.player.LuckCards = append(t.player.LuckCards, cardId)
 		return cardId, nil
@@ -507,9 +506,9 @@ func (g *gogame) turn_takeluck(t *turn, c game.CommandPattern, args []string) (i
 		g.moveMoney(g.bank.Money, t.player.Money, code.CurrencyId, amount)
 	case LuckSpeculation:
 		// TODO
-		t.addEvent("needs to think about how to implement this luck card")
+		t.addEvent("luckspeculation", nil)
 	case LuckCode:
-		t.addEvent("finds out that his luck card is unimplemented")
+		t.addEvent("luckunimplemented", nil)
 	default:
 		panic("bad luck card " + card.Code)
 	}
@@ -522,13 +521,13 @@ func (g *gogame) turn_takerisk(t *turn, c game.CommandPattern, args []string) (i
 
 	cardId, pile := g.riskPile.Take()
 	if cardId < 0 {
-		t.addEvent("finds no risk cards")
+		t.addEvent("norisk", nil)
 		return nil, nil
 	}
 	g.riskPile = pile
 
 	card := g.risks[cardId]
-	t.addEventf("takes a risk card: %s", card.Name)
+	t.addEvent("takerisk", game.Params{"card": cardId})
 
 	// make sure all risk cards are returned
 	defer func() { g.riskPile = g.riskPile.Return(cardId) }()
@@ -605,7 +604,7 @@ func (g *gogame) turn_useluck(t *turn, c game.CommandPattern, args []string) (in
 		t.player.LuckCards = luckList
 		g.luckPile = g.luckPile.Return(cardId)
 
-		t.addEvent("luckily arrives early")
+		t.addEvent("luckarriveearly", nil)
 	case LuckFreeInsurance:
 		if t.LostTicket == nil {
 			return nil, game.Error(game.StatusNotNow, "cannot claim insurance when no ticket lost")
@@ -622,7 +621,7 @@ func (g *gogame) turn_useluck(t *turn, c game.CommandPattern, args []string) (in
 		t.player.LuckCards = luckList
 		g.luckPile = g.luckPile.Return(cardId)
 
-		t.addEvent("luckily gets a big refund")
+		t.addEvent("luckrefund", nil)
 	case LuckFreeTicket:
 		if t.player.Ticket != nil {
 			return nil, game.Error(game.StatusNotNow, "cannot claim free ticket when already have ticket")
@@ -650,7 +649,7 @@ func (g *gogame) turn_useluck(t *turn, c game.CommandPattern, args []string) (in
 		t.player.LuckCards = luckList
 		g.luckPile = g.luckPile.Return(cardId)
 
-		t.addEventf("luckily gets a ticket to %s by %s", to, modes)
+		t.addEvent("luckticket", game.Params{"to": to, "modes": modes})
 	case LuckImmunity:
 		must, changed := stringListWithout(t.Must, "declare:*")
 		if !changed {
@@ -664,7 +663,7 @@ func (g *gogame) turn_useluck(t *turn, c game.CommandPattern, args []string) (in
 		t.player.LuckCards = luckList
 		g.luckPile = g.luckPile.Return(cardId)
 
-		t.addEvent("luckily dodges the customs checks")
+		t.addEvent("luckcustoms", nil)
 	case LuckInoculation:
 		must, changed := stringListWithout(t.Must, "quarantine")
 		if !changed {
@@ -675,12 +674,12 @@ func (g *gogame) turn_useluck(t *turn, c game.CommandPattern, args []string) (in
 		t.player.LuckCards = luckList
 		g.luckPile = g.luckPile.Return(cardId)
 
-		t.addEvent("luckily avoids quarantine")
+		t.addEvent("luckquarantine", nil)
 	case LuckCode:
 		t.player.LuckCards = luckList
 		g.luckPile = g.luckPile.Return(cardId)
 
-		t.addEvent("finds out that his luck card is unimplemented")
+		t.addEvent("luckunimplemented", nil)
 	default:
 		panic("bad luck card " + card.Code)
 	}
@@ -696,6 +695,6 @@ func (g *gogame) turn_end(t *turn, c game.CommandPattern, args []string) (interf
 		return nil, game.Error(game.StatusMustDo, "")
 	}
 	g.toNextPlayer()
-	t.addEvent("goes to sleep")
+	t.addEvent("end", nil)
 	return nil, nil
 }
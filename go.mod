@@ -8,6 +8,7 @@ require (
 	github.com/chzyer/test v0.0.0-20210722231415-061457976a23 // indirect
 	github.com/gin-gonic/gin v1.6.3
 	github.com/rs/zerolog v1.25.0
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 	golang.org/x/sys v0.0.0-20210915083310-ed5796bab164 // indirect
 	google.golang.org/grpc v1.42.0
 	google.golang.org/protobuf v1.27.1
@@ -0,0 +1,8 @@
+// Package rummyassets has the web client for the rummy game, so that the game
+// can be deployed as one binary.
+package rummyassets
+
+import "embed"
+
+//go:embed web
+var FS embed.FS
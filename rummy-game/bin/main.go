@@ -4,11 +4,12 @@ import (
 	"io"
 
 	"github.com/undeconstructed/gogogo/game"
+	"github.com/undeconstructed/gogogo/rummy-game"
 	"github.com/undeconstructed/gogogo/rummy-game/lib"
 )
 
 func main() {
-	game.GRPCMain(func(options map[string]interface{}) (game.Game, error) {
+	game.GRPCMain(rummygame.Description(), rummyassets.FS, func(options map[string]interface{}) (game.Game, error) {
 		return rummygame.NewGame(), nil
 	}, func(in io.Reader) (game.Game, error) {
 		return rummygame.NewFromSaved(in)
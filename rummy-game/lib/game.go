@@ -9,6 +9,17 @@ import (
 type rummygame struct {
 }
 
+// Description describes the game, which has no options yet.
+func Description() game.Description {
+	return game.Description{
+		Name:        "rummy",
+		DisplayName: "Rummy",
+		MinPlayers:  2,
+		MaxPlayers:  6,
+		Version:     "1",
+	}
+}
+
 func NewGame() game.Game {
 	g := &rummygame{}
 
@@ -6,11 +6,14 @@ function showMessage(msg) {
   div.setAttribute('show', 'message')
 }
 
-function doCreate(options, players) {
+function doCreate(token, options, players) {
   let js = JSON.stringify({ 'type': 'rummy', options, players })
-  fetch('/api/games', { method: 'POST', body: js }).
+  let headers = { 'Authorization': `Bearer ${token}` }
+  fetch('/api/games', { method: 'POST', headers, body: js }).
     then(rez => {
-      if (!rez.ok) {
+      if (rez.status === 401) {
+        showMessage('bad token')
+      } else if (!rez.ok) {
         rez.json().then(j => {
           showMessage(j.error.message)
         })
@@ -69,6 +72,7 @@ function main() {
 
   form.addEventListener('submit', e => {
     e.preventDefault()
+    let token = form.querySelector('[name=token]').value
     let players = []
     for (let p of playersDiv.querySelectorAll('.player')) {
       let n = p.querySelector('input').value
@@ -76,7 +80,7 @@ function main() {
     }
     inpDiv.style.display = 'none'
     showMessage('... working ...')
-    doCreate({}, players)
+    doCreate(token, {}, players)
   })
 
   div.setAttribute('show', 'input')
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// loginTTL is how long a login token lasts
+const loginTTL = 30 * 24 * time.Hour
+
+var (
+	errAccountExists = errors.New("account exists")
+	errBadLogin      = errors.New("bad username or password")
+	errBadUsername   = errors.New("bad username")
+	errBadPassword   = errors.New("password too short")
+	errNoAccount     = errors.New("no such account")
+	errSeatTaken     = errors.New("seat belongs to someone else")
+	errNoSeat        = errors.New("no such player")
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_]{2,20}$`)
+
+// account is someone who can play in many games.
+type account struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	Hash        []byte `json:"hash"`
+}
+
+// AccountInfo is the public view of an account.
+type AccountInfo struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+}
+
+type login struct {
+	username string
+	expires  time.Time
+}
+
+// accountStore holds accounts, saved in a file, and login tokens, which are
+// only kept in memory. It is used directly by the gateways, to check tokens,
+// so has its own lock rather than going through the core.
+type accountStore struct {
+	file string
+
+	l        sync.Mutex
+	accounts map[string]*account
+	logins   map[string]login
+	// names that can't be used, e.g. the API token users
+	reserved map[string]bool
+}
+
+// loadAccounts opens the account file, which need not exist yet.
+func loadAccounts(file string, reserved []string) (*accountStore, error) {
+	a := &accountStore{
+		file:     file,
+		accounts: map[string]*account{},
+		logins:   map[string]login{},
+		reserved: map[string]bool{adminUser: true},
+	}
+	for _, name := range reserved {
+		a.reserved[name] = true
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return a, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var list []*account
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, err
+	}
+	for _, acc := range list {
+		a.accounts[acc.Username] = acc
+	}
+	return a, nil
+}
+
+// save writes out all the accounts. The lock must be held.
+func (a *accountStore) save() error {
+	list := []*account{}
+	for _, acc := range a.accounts {
+		list = append(list, acc)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Username < list[j].Username })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(a.file), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(a.file, data, 0600)
+}
+
+// Create makes a new account.
+func (a *accountStore) Create(username, password, displayName string) (AccountInfo, error) {
+	if !usernamePattern.MatchString(username) {
+		return AccountInfo{}, errBadUsername
+	}
+	if len(password) < 8 {
+		return AccountInfo{}, errBadPassword
+	}
+	if displayName == "" {
+		displayName = username
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return AccountInfo{}, err
+	}
+
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	if _, exists := a.accounts[username]; exists || a.reserved[username] {
+		return AccountInfo{}, errAccountExists
+	}
+
+	acc := &account{username, displayName, hash}
+	a.accounts[username] = acc
+	err = a.save()
+	if err != nil {
+		delete(a.accounts, username)
+		return AccountInfo{}, err
+	}
+
+	return AccountInfo{acc.Username, acc.DisplayName}, nil
+}
+
+// Login checks a password, and makes a token for using the API.
+func (a *accountStore) Login(username, password string) (string, time.Time, error) {
+	a.l.Lock()
+	acc, ok := a.accounts[username]
+	a.l.Unlock()
+	if !ok {
+		return "", time.Time{}, errBadLogin
+	}
+
+	err := bcrypt.CompareHashAndPassword(acc.Hash, []byte(password))
+	if err != nil {
+		return "", time.Time{}, errBadLogin
+	}
+
+	b := make([]byte, 24)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	expires := time.Now().Add(loginTTL)
+
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.logins[token] = login{username, expires}
+
+	return token, expires, nil
+}
+
+// Logout forgets a token.
+func (a *accountStore) Logout(token string) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	delete(a.logins, token)
+}
+
+// Lookup finds the account for a login token.
+func (a *accountStore) Lookup(token string) (string, bool) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	l, ok := a.logins[token]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(l.expires) {
+		delete(a.logins, token)
+		return "", false
+	}
+	return l.username, true
+}
+
+// Get finds an account by name.
+func (a *accountStore) Get(username string) (AccountInfo, bool) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	acc, ok := a.accounts[username]
+	if !ok {
+		return AccountInfo{}, false
+	}
+	return AccountInfo{acc.Username, acc.DisplayName}, true
+}
+
+// checkAccounts checks that seats are only linked to accounts that exist.
+func (s *server) checkAccounts(players []MakePlayerInput) error {
+	for _, pl := range players {
+		if pl.Account == "" {
+			continue
+		}
+		if pl.Bot != "" {
+			return errors.New("bots can't have accounts")
+		}
+		if s.accounts == nil {
+			return errors.New("accounts are not enabled")
+		}
+		if _, ok := s.accounts.Get(pl.Account); !ok {
+			return errNoAccount
+		}
+	}
+	return nil
+}
+
+func (s *server) doMyGames(in myGamesMsg) {
+	list := []MyGame{}
+	for _, g := range s.games {
+		for seat, username := range g.meta.Accounts {
+			if username != in.User {
+				continue
+			}
+			mg := MyGame{
+				ID:   g.id,
+				Type: g.gameType,
+				Seat: seat,
+				Code: encodeConnectString(g.id, seat),
+			}
+			if g.state != nil {
+				mg.Status = game.GameStatus(g.state.Status)
+				mg.Playing = g.state.Playing
+				mg.YourTurn = g.state.Status == string(game.StatusInProgress) && g.state.Playing == seat
+			}
+			list = append(list, mg)
+		}
+	}
+	// my turn first, then by id
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].YourTurn != list[j].YourTurn {
+			return list[i].YourTurn
+		}
+		return list[i].ID < list[j].ID
+	})
+	in.Rep <- list
+}
+
+// doLinkSeat links a seat to an account, for the game's owner, so that the
+// account can find it. A seat that someone is already playing can't be given
+// to an account, as that would let the account take it from them.
+func (s *server) doLinkSeat(in linkSeatMsg) {
+	g, ok := s.games[in.Game]
+	if !ok {
+		in.Rep <- errGameNotFound
+		return
+	}
+
+	if !in.User.CanDelete(g.meta.Owner) {
+		in.Rep <- errNotAllowed
+		return
+	}
+
+	found := false
+	if g.state != nil {
+		for _, pl := range g.state.Players {
+			if pl.Name == in.Seat {
+				found = true
+			}
+		}
+	}
+	if !found || g.meta.Bots[in.Seat] != "" {
+		in.Rep <- errNoSeat
+		return
+	}
+
+	if _, ok := s.accounts.Get(in.Account); !ok {
+		in.Rep <- errNoAccount
+		return
+	}
+
+	if other, ok := g.meta.Accounts[in.Seat]; ok {
+		if other == in.Account {
+			in.Rep <- nil
+		} else {
+			in.Rep <- errSeatTaken
+		}
+		return
+	}
+	if _, playing := g.sessions[in.Seat]; playing {
+		in.Rep <- errSeatTaken
+		return
+	}
+
+	if g.meta.Accounts == nil {
+		g.meta.Accounts = map[string]string{}
+	}
+	g.meta.Accounts[in.Seat] = in.Account
+
+	err := saveMeta(g.gameType, g.id, g.meta)
+	if err != nil {
+		g.log.Err(err).Msg("instance meta save failed")
+	}
+
+	in.Rep <- nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"path"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestAccountStore(t *testing.T) {
+	file := path.Join(t.TempDir(), "accounts.json")
+
+	a, err := loadAccounts(file, []string{"phil"})
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	if _, err := a.Create("phil", "password1", ""); err != errAccountExists {
+		t.Errorf("token user should be reserved, got: %v", err)
+	}
+	if _, err := a.Create("X Y", "password1", ""); err != errBadUsername {
+		t.Errorf("expected bad username, got: %v", err)
+	}
+	if _, err := a.Create("anna", "short", ""); err != errBadPassword {
+		t.Errorf("expected bad password, got: %v", err)
+	}
+
+	info, err := a.Create("anna", "password1", "Anna")
+	if err != nil {
+		t.Fatalf("create error: %v", err)
+	}
+	if info.Username != "anna" || info.DisplayName != "Anna" {
+		t.Errorf("bad info: %v", info)
+	}
+	if _, err := a.Create("anna", "password2", ""); err != errAccountExists {
+		t.Errorf("expected exists, got: %v", err)
+	}
+
+	if _, _, err := a.Login("anna", "wrong"); err != errBadLogin {
+		t.Errorf("expected bad login, got: %v", err)
+	}
+	token, _, err := a.Login("anna", "password1")
+	if err != nil {
+		t.Fatalf("login error: %v", err)
+	}
+	if name, ok := a.Lookup(token); !ok || name != "anna" {
+		t.Errorf("bad lookup: %s %t", name, ok)
+	}
+	a.Logout(token)
+	if _, ok := a.Lookup(token); ok {
+		t.Errorf("token should be gone")
+	}
+
+	// accounts are saved, but logins aren't
+	b, err := loadAccounts(file, nil)
+	if err != nil {
+		t.Fatalf("reload error: %v", err)
+	}
+	if _, ok := b.Get("anna"); !ok {
+		t.Errorf("account not saved")
+	}
+	if _, _, err := b.Login("anna", "password1"); err != nil {
+		t.Errorf("login after reload error: %v", err)
+	}
+}
+
+func TestLinkSeat(t *testing.T) {
+	inTempDir(t)
+
+	accounts, err := loadAccounts(path.Join("run", "accounts.json"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts.Create("anna", "password1", "")
+	accounts.Create("bert", "password1", "")
+
+	s := &server{games: map[string]*instance{}, accounts: accounts}
+
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Owner: "phil", Bots: map[string]string{"robo": "random"}}
+	g.state = &game.RGameState{
+		Players: []*game.RPlayerState{{Name: "a"}, {Name: "b"}, {Name: "robo"}},
+	}
+	g.sessions["b"] = newSession(false)
+	s.games[g.id] = g
+
+	link := func(seat, account string, user authUser) error {
+		rep := make(chan error, 1)
+		s.doLinkSeat(linkSeatMsg{"g1", seat, account, user, rep})
+		return <-rep
+	}
+
+	owner := authUser{Name: "phil"}
+	if err := link("a", "anna", authUser{Name: "anna"}); err != errNotAllowed {
+		t.Errorf("expected only the owner to link, got %v", err)
+	}
+	if err := link("a", "anna", owner); err != nil || g.meta.Accounts["a"] != "anna" {
+		t.Errorf("expected linked, got %v", err)
+	}
+	if err := link("a", "bert", authUser{Name: adminUser, Admin: true}); err != errSeatTaken {
+		t.Errorf("expected taken, got %v", err)
+	}
+	// someone has the connect code already
+	if err := link("b", "bert", owner); err != errSeatTaken {
+		t.Errorf("expected taken while being played, got %v", err)
+	}
+	if err := link("robo", "bert", owner); err != errNoSeat {
+		t.Errorf("expected no seat for bot, got %v", err)
+	}
+	if err := link("a", "nobody", owner); err != errNoAccount {
+		t.Errorf("expected no account, got %v", err)
+	}
+}
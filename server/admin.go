@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sort"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+var errGameNotFound = errors.New("game not found")
+var errTypeNotFound = errors.New("game type not found")
+
+func (s *server) doAdminList(in adminListMsg) {
+	list := []InstanceInfo{}
+	for _, g := range s.games {
+		list = append(list, g.Info())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	in.Rep <- list
+}
+
+func (s *server) doAdminRestart(in adminRestartMsg) {
+	g, ok := s.games[in.Game]
+	if !ok {
+		in.Rep <- errGameNotFound
+		return
+	}
+
+	pool, h, id := g.pool, g.host, g.id
+	go func() {
+		h, state, err := restartGame(context.TODO(), pool, h, id)
+		if err != nil {
+			g.log.Err(err).Msg("instance restart failed")
+		}
+		s.coreCh <- afterRestart{in, g, h, state, err}
+	}()
+}
+
+func (s *server) afterAdminRestart(in afterRestart) (*instance, []game.Change) {
+	g := in.game
+	if in.err != nil {
+		// it's in no process now, so a retry gets it a new place
+		g.host = nil
+		in.in.Rep <- in.err
+		return nil, nil
+	}
+
+	if s.games[g.id] != g {
+		// gone while restarting, so it mustn't come back
+		g.host = in.host
+		g.Shutdown()
+		in.in.Rep <- errGameNotFound
+		return nil, nil
+	}
+
+	g.host = in.host
+	g.state = in.state
+	in.in.Rep <- nil
+
+	return g, []game.Change{{What: "the game is restarted"}}
+}
+
+// doAdminUpgrade moves all the games of a type into new plugin processes, from
+// the binary as it is now, e.g. after a fix has been put in place of it.
+// Players stay connected, and their moves wait while it happens. A binary with
+// a different save version is refused, as the games' saves wouldn't load.
+func (s *server) doAdminUpgrade(in adminUpgradeMsg) {
+	if !stringListContains(s.gameTypes, in.Type) {
+		in.Rep <- errTypeNotFound
+		return
+	}
+
+	version := s.types[in.Type].Version
+	pool := s.pool(in.Type)
+	go func() {
+		desc, assets, err := pool.describe(context.TODO())
+		if err != nil {
+			pool.log.Err(err).Msg("cannot describe new binary")
+			s.coreCh <- afterUpgrade{in: in, err: err}
+			return
+		}
+		if desc.Version != version {
+			err := game.Errorf(game.StatusConflict, "binary is version %q, but %s is version %q", desc.Version, in.Type, version)
+			s.coreCh <- afterUpgrade{in: in, err: err}
+			return
+		}
+
+		// new processes are from the new binary, even if moving the games
+		// fails, so the description is the new one either way
+		err = pool.upgrade()
+		if err != nil {
+			pool.log.Err(err).Msg("upgrade failed")
+		}
+		s.coreCh <- afterUpgrade{in, &desc, assets, err}
+	}()
+}
+
+func (s *server) afterAdminUpgrade(in afterUpgrade) {
+	if in.desc != nil {
+		in.desc.Name = in.in.Type
+		s.types[in.in.Type] = *in.desc
+
+		s.assetsLock.Lock()
+		s.assets[in.in.Type] = in.assets
+		s.assetsLock.Unlock()
+	}
+	in.in.Rep <- in.err
+}
+
+func (s *server) doAdminSave(in adminSaveMsg) {
+	g, ok := s.games[in.Game]
+	if !ok {
+		in.Rep <- adminSaveResult{Err: errGameNotFound}
+		return
+	}
+
+	file := g.SaveFile()
+	go func() {
+		data, err := ioutil.ReadFile(file)
+		in.Rep <- adminSaveResult{data, err}
+	}()
+}
+
+func (s *server) doAdminEnd(in adminEndMsg) {
+	g, ok := s.games[in.Game]
+	if !ok {
+		in.Rep <- errGameNotFound
+		return
+	}
+
+	if in.Archive {
+		var status game.GameStatus = game.StatusUnstarted
+		if g.state != nil {
+			status = game.GameStatus(g.state.Status)
+		}
+		err := s.archiveGame(g, status)
+		if err != nil {
+			in.Rep <- err
+			return
+		}
+	} else {
+		err := g.Shutdown()
+		if err != nil {
+			in.Rep <- err
+			return
+		}
+
+		g.dropClients()
+	}
+
+	g.log.Info().Msgf("instance ended, archive: %t", in.Archive)
+
+	in.Rep <- nil
+}
+
+func (s *server) doAdminKick(in adminKickMsg) {
+	g, ok := s.games[in.Game]
+	if !ok {
+		in.Rep <- errGameNotFound
+		return
+	}
+
+	client, ok := g.clients[in.Name]
+	if !ok {
+		in.Rep <- errors.New("client not connected")
+		return
+	}
+
+	// closing the channel makes the gateway drop the connection
+	close(client.downCh)
+	delete(g.clients, in.Name)
+
+	g.log.Info().Msgf("client kicked: %s", in.Name)
+
+	in.Rep <- nil
+}
+
+func (s *server) AdminListInstances() []InstanceInfo {
+	resCh := make(chan []InstanceInfo)
+	s.coreCh <- adminListMsg{resCh}
+	return <-resCh
+}
+
+func (s *server) AdminRestart(id string) error {
+	resCh := make(chan error)
+	s.coreCh <- adminRestartMsg{id, resCh}
+	return <-resCh
+}
+
+func (s *server) AdminUpgrade(gameType string) error {
+	resCh := make(chan error)
+	s.coreCh <- adminUpgradeMsg{gameType, resCh}
+	return <-resCh
+}
+
+func (s *server) AdminDumpSave(id string) ([]byte, error) {
+	resCh := make(chan adminSaveResult)
+	s.coreCh <- adminSaveMsg{id, resCh}
+	res := <-resCh
+	return res.Data, res.Err
+}
+
+func (s *server) AdminEnd(id string, archive bool) error {
+	resCh := make(chan error)
+	s.coreCh <- adminEndMsg{id, archive, resCh}
+	return <-resCh
+}
+
+func (s *server) AdminKick(id, name string) error {
+	resCh := make(chan error)
+	s.coreCh <- adminKickMsg{id, name, resCh}
+	return <-resCh
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// runTestCore does what the server's main loop does, for the messages that
+// tests send.
+func runTestCore(s *server) {
+	s.coreCh = make(chan interface{}, 10)
+	go func() {
+		for in := range s.coreCh {
+			switch msg := in.(type) {
+			case adminListMsg:
+				s.doAdminList(msg)
+			case adminEndMsg:
+				s.doAdminEnd(msg)
+			case adminRestartMsg:
+				s.doAdminRestart(msg)
+			case afterRestart:
+				s.afterAdminRestart(msg)
+			case adminUpgradeMsg:
+				s.doAdminUpgrade(msg)
+			case afterUpgrade:
+				s.afterAdminUpgrade(msg)
+			case deleteGameMsg:
+				s.doDeleteGame(msg)
+			}
+		}
+	}()
+}
+
+func TestAdminEnd(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	inTempDir(t)
+
+	s := &server{games: map[string]*instance{}}
+	runTestCore(s)
+	defer close(s.coreCh)
+
+	g := newInstance("go", "g1")
+	g.state = &game.RGameState{Status: string(game.StatusInProgress)}
+	downCh := make(chan interface{}, 1)
+	g.clients["phil"] = &clientBundle{downCh}
+	s.games[g.id] = g
+
+	ah := &adminHandler{server: s}
+	r := gin.New()
+	r.GET("/instances", ah.getInstances)
+	r.POST("/instances/:id/end", ah.endInstance)
+
+	do := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, url, nil))
+		return w
+	}
+
+	if w := do(http.MethodPost, "/instances/g2/end"); w.Code != http.StatusNotFound {
+		t.Errorf("expected not found, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/instances/g1/end"); w.Code != http.StatusOK {
+		t.Fatalf("expected ok, got %d %s", w.Code, w.Body)
+	}
+	if _, ok := <-downCh; ok {
+		t.Errorf("expected client dropped")
+	}
+
+	// the game stays, not running, and says so rather than crashing
+	w := do(http.MethodGet, "/instances")
+	var list []InstanceInfo
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list) != 1 || list[0].ID != "g1" || list[0].Health != "none" {
+		t.Errorf("bad list: %s", w.Body)
+	}
+	if _, _, err := g.Play(g.host, "phil", game.Command{Command: "dicemove"}); err != errNotRunning {
+		t.Errorf("expected not running, got %v", err)
+	}
+	if err := g.Start(g.host); err != errNotRunning {
+		t.Errorf("expected not running, got %v", err)
+	}
+
+	// and can still be deleted
+	os.MkdirAll(path.Dir(g.SaveFile()), 0755)
+	os.WriteFile(g.SaveFile(), []byte("{}"), 0644)
+	if err := g.Destroy(); err != nil {
+		t.Errorf("expected destroyed, got %v", err)
+	}
+	if _, err := os.Stat(g.SaveFile()); !os.IsNotExist(err) {
+		t.Errorf("expected save gone, got %v", err)
+	}
+}
+
+func TestAfterAdminRestart(t *testing.T) {
+	s := &server{games: map[string]*instance{}}
+
+	g := newInstance("go", "g1")
+	s.games[g.id] = g
+
+	rep := make(chan error, 1)
+	state := &game.RGameState{Status: string(game.StatusInProgress)}
+	g2, news := s.afterAdminRestart(afterRestart{in: adminRestartMsg{"g1", rep}, game: g, state: state})
+	if err := <-rep; err != nil || g2 != g || len(news) != 1 {
+		t.Errorf("expected restarted, got %v %v", err, news)
+	}
+	if g.state != state {
+		t.Errorf("expected state from restart")
+	}
+
+	// gone while restarting
+	delete(s.games, g.id)
+	g2, _ = s.afterAdminRestart(afterRestart{in: adminRestartMsg{"g1", rep}, game: g, state: state})
+	if err := <-rep; err != errGameNotFound || g2 != nil {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
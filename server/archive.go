@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/rs/zerolog/log"
+)
+
+var errNotArchived = errors.New("game not in archive")
+
+// ArchiveSummary is what the archive listing shows about a game.
+type ArchiveSummary struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Owner    string          `json:"owner"`
+	Created  time.Time       `json:"created"`
+	Finished time.Time       `json:"finished"`
+	Status   game.GameStatus `json:"status"`
+	Winner   string          `json:"winner,omitempty"`
+	Players  []string        `json:"players"`
+}
+
+// ArchivedGame is a game that is over, with everything needed to look back at
+// it, but not to play it.
+type ArchivedGame struct {
+	ArchiveSummary
+	Bots     map[string]string `json:"bots,omitempty"`
+	Accounts map[string]string `json:"accounts,omitempty"`
+	State    *game.GameState   `json:"state"`
+	News     []game.Change     `json:"news"`
+}
+
+func archiveDir(gameType string) string {
+	return path.Join("run", gameType, "archive")
+}
+
+func archiveFileName(gameType, id string) string {
+	return path.Join(archiveDir(gameType), id+".json")
+}
+
+// archiveSaveFileName is where the plugin's own save goes, in case it's ever
+// wanted.
+func archiveSaveFileName(gameType, id string) string {
+	return path.Join(archiveDir(gameType), id+".save")
+}
+
+func newsFileName(gameType, id string) string {
+	return path.Join("run", gameType, "news", id+".jsonl")
+}
+
+// appendNews adds news to a game's log, which is kept for the archive.
+func appendNews(gameType, id string, news []game.Change) error {
+	file := newsFileName(gameType, id)
+	err := os.MkdirAll(path.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, n := range news {
+		err := enc.Encode(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadNews(gameType, id string) ([]game.Change, error) {
+	f, err := os.Open(newsFileName(gameType, id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var news []game.Change
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		n := game.Change{}
+		if err := json.Unmarshal(scanner.Bytes(), &n); err != nil {
+			return nil, err
+		}
+		news = append(news, n)
+	}
+	return news, scanner.Err()
+}
+
+// loadArchiveIndex reads the summaries of every archived game.
+func loadArchiveIndex(gameTypes []string) map[string]ArchiveSummary {
+	index := map[string]ArchiveSummary{}
+	for _, gt := range gameTypes {
+		files, err := ioutil.ReadDir(archiveDir(gt))
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if !strings.HasSuffix(f.Name(), ".json") {
+				continue
+			}
+			data, err := ioutil.ReadFile(path.Join(archiveDir(gt), f.Name()))
+			if err != nil {
+				log.Warn().Err(err).Msgf("can't read archive file: %s", f.Name())
+				continue
+			}
+			sum := ArchiveSummary{}
+			if err := json.Unmarshal(data, &sum); err != nil || sum.ID == "" {
+				log.Warn().Msgf("not an archive file: %s", f.Name())
+				continue
+			}
+			index[sum.ID] = sum
+		}
+	}
+	return index
+}
+
+func loadArchived(gameType, id string) (*ArchivedGame, error) {
+	data, err := ioutil.ReadFile(archiveFileName(gameType, id))
+	if err != nil {
+		return nil, err
+	}
+	a := &ArchivedGame{}
+	err = json.Unmarshal(data, a)
+	return a, err
+}
+
+// finalState is the last state of a game, for looking back at. Global and
+// private state are already JSON.
+func finalState(in *game.RGameState) *game.GameState {
+	out := &game.GameState{
+		Status:     game.GameStatus(in.Status),
+		Playing:    in.Playing,
+		Winner:     in.Winner,
+		TurnNumber: int(in.TurnNumber),
+		Global:     orNull(in.Global),
+	}
+	for _, pl := range in.Players {
+		out.Players = append(out.Players, game.PlayerState{
+			Name:    pl.Name,
+			Turn:    game.UnwrapTurnState(pl.Turn),
+			Private: orNull(pl.Private),
+		})
+	}
+	return out
+}
+
+// scheduleComplete completes a won game after a while, to give the players
+// time to see that it's over.
+func (s *server) scheduleComplete(g *instance) {
+	id := g.id
+	time.AfterFunc(s.completeAfter, func() {
+		s.coreCh <- completeGameMsg{id}
+	})
+}
+
+func (s *server) doCompleteGame(in completeGameMsg) {
+	g, ok := s.games[in.Game]
+	if !ok || g.state == nil || g.state.Status != string(game.StatusWon) {
+		// gone already, or restarted or something
+		return
+	}
+
+	err := s.archiveGame(g, game.StatusComplete)
+	if err != nil {
+		g.log.Err(err).Msg("instance archive failed")
+		return
+	}
+	g.log.Info().Msg("instance complete")
+}
+
+// archiveGame stops a game for good, and moves it to the archive.
+func (s *server) archiveGame(g *instance, status game.GameStatus) error {
+	err := g.Shutdown()
+	if err != nil {
+		return err
+	}
+
+	g.dropClients()
+	s.dropHooks(g)
+
+	a := ArchivedGame{
+		ArchiveSummary: ArchiveSummary{
+			ID:       g.id,
+			Type:     g.gameType,
+			Owner:    g.meta.Owner,
+			Created:  g.meta.Created,
+			Finished: time.Now(),
+			Status:   status,
+		},
+		Bots:     g.meta.Bots,
+		Accounts: g.meta.Accounts,
+	}
+	if g.state != nil {
+		a.Winner = g.state.Winner
+		a.State = finalState(g.state)
+		for _, pl := range g.state.Players {
+			a.Players = append(a.Players, pl.Name)
+		}
+	}
+	a.News, err = loadNews(g.gameType, g.id)
+	if err != nil {
+		g.log.Err(err).Msg("cannot read news log")
+	}
+
+	err = os.MkdirAll(archiveDir(g.gameType), 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(archiveFileName(g.gameType, g.id), data, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(g.SaveFile(), archiveSaveFileName(g.gameType, g.id))
+	if err != nil {
+		g.log.Err(err).Msg("cannot move save file")
+	}
+	os.Remove(newsFileName(g.gameType, g.id))
+	err = removeMeta(g.gameType, g.id)
+	if err != nil {
+		g.log.Err(err).Msg("meta delete failed")
+	}
+
+	delete(s.games, g.id)
+	s.archive[g.id] = a.ArchiveSummary
+
+	return nil
+}
+
+func (s *server) doListArchive(in listArchiveMsg) {
+	list := []ArchiveSummary{}
+	for _, a := range s.archive {
+		if in.Type == "" || a.Type == in.Type {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Finished.After(list[j].Finished) })
+	in.Rep <- list
+}
+
+func (s *server) doGetArchived(in getArchivedMsg) {
+	sum, ok := s.archive[in.Game]
+	if !ok {
+		in.Rep <- getArchivedResult{Err: errNotArchived}
+		return
+	}
+
+	go func() {
+		a, err := loadArchived(sum.Type, sum.ID)
+		in.Rep <- getArchivedResult{a, err}
+	}()
+}
+
+// doPurgeArchive deletes archived games that are older than the retention
+// time, if there is one.
+func (s *server) doPurgeArchive(in purgeArchiveMsg) {
+	if s.archiveRetention <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-s.archiveRetention)
+	for id, a := range s.archive {
+		if a.Finished.After(cutoff) {
+			continue
+		}
+		for _, file := range []string{archiveFileName(a.Type, id), archiveSaveFileName(a.Type, id)} {
+			err := os.Remove(file)
+			if err != nil && !os.IsNotExist(err) {
+				log.Err(err).Msgf("cannot purge archive file: %s", file)
+			}
+		}
+		delete(s.archive, id)
+		log.Info().Msgf("purged archived game: %s", id)
+	}
+}
+
+func (s *server) ListArchive(gameType string) []ArchiveSummary {
+	resCh := make(chan []ArchiveSummary)
+	s.coreCh <- listArchiveMsg{gameType, resCh}
+	return <-resCh
+}
+
+func (s *server) GetArchived(id string) (*ArchivedGame, error) {
+	resCh := make(chan getArchivedResult)
+	s.coreCh <- getArchivedMsg{id, resCh}
+	res := <-resCh
+	return res.Game, res.Err
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// inTempDir runs a test in an empty dir, as the run dir is relative.
+func inTempDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestArchive(t *testing.T) {
+	inTempDir(t)
+
+	s := &server{
+		games:   map[string]*instance{},
+		archive: map[string]ArchiveSummary{},
+	}
+
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Owner: "phil", Bots: map[string]string{"b": "random"}}
+	g.state = &game.RGameState{
+		Status:  string(game.StatusWon),
+		Winner:  "a",
+		Global:  []byte(`{"x":1}`),
+		Players: []*game.RPlayerState{{Name: "a"}, {Name: "b"}},
+	}
+	s.games[g.id] = g
+
+	err := saveMeta("go", "g1", g.meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendNews("go", "g1", []game.Change{{Who: "a", What: "started"}})
+	appendNews("go", "g1", []game.Change{{Who: "a", What: "won"}})
+
+	s.doCompleteGame(completeGameMsg{"g1"})
+
+	if _, ok := s.games["g1"]; ok {
+		t.Errorf("game not removed")
+	}
+	if _, err := os.Stat(newsFileName("go", "g1")); !os.IsNotExist(err) {
+		t.Errorf("news log not removed")
+	}
+	if _, err := os.Stat(metaFileName("go", "g1")); !os.IsNotExist(err) {
+		t.Errorf("meta not removed")
+	}
+
+	resCh := make(chan getArchivedResult)
+	s.doGetArchived(getArchivedMsg{"g1", resCh})
+	res := <-resCh
+	if res.Err != nil {
+		t.Fatalf("get error: %v", res.Err)
+	}
+	a := res.Game
+	if a.Status != game.StatusComplete || a.Winner != "a" || a.Owner != "phil" || len(a.Players) != 2 {
+		t.Errorf("bad archive: %+v", a.ArchiveSummary)
+	}
+	if len(a.News) != 2 || a.News[1].What != "won" {
+		t.Errorf("bad news: %v", a.News)
+	}
+	if global, ok := a.State.Global.(map[string]interface{}); !ok || global["x"] != 1.0 {
+		t.Errorf("bad state: %v", a.State.Global)
+	}
+
+	// the index is rebuilt from the files
+	index := loadArchiveIndex([]string{"go", "rummy"})
+	if index["g1"].Winner != "a" {
+		t.Errorf("bad index: %v", index)
+	}
+
+	// nothing is purged without a retention time
+	s.doPurgeArchive(purgeArchiveMsg{})
+	if len(s.archive) != 1 {
+		t.Errorf("purged too soon")
+	}
+
+	s.archiveRetention = time.Hour
+	sum := s.archive["g1"]
+	sum.Finished = time.Now().Add(-2 * time.Hour)
+	s.archive["g1"] = sum
+	s.doPurgeArchive(purgeArchiveMsg{})
+	if len(s.archive) != 0 {
+		t.Errorf("not purged")
+	}
+	if _, err := os.Stat(archiveFileName("go", "g1")); !os.IsNotExist(err) {
+		t.Errorf("archive file not removed")
+	}
+}
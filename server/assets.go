@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// asset is a file from a game plugin, ready to serve.
+type asset struct {
+	data        []byte
+	contentType string
+	etag        string
+}
+
+func newAsset(name string, data []byte) *asset {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	sum := sha256.Sum256(data)
+	return &asset{
+		data:        data,
+		contentType: contentType,
+		etag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}
+}
+
+// fetchAssets gets every file that a plugin has for serving. Plugins from
+// before assets existed have none.
+func fetchAssets(ctx context.Context, cli game.InstanceClient) (map[string]*asset, error) {
+	list, err := cli.ListAssets(ctx, &game.RListAssetsRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	assets := map[string]*asset{}
+	for _, p := range list.Paths {
+		res, err := cli.GetAsset(ctx, &game.RGetAssetRequest{Path: p})
+		if err != nil {
+			return nil, err
+		}
+		assets[p] = newAsset(p, res.Data)
+	}
+	return assets, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// adminUser is the name used for whoever holds the admin token.
+const adminUser = "admin"
+
+var errNotAllowed = errors.New("not allowed")
+
+// authUser is someone who has presented a valid token.
+type authUser struct {
+	Name  string
+	Admin bool
+}
+
+// CanDelete says whether this user can delete a game with the given owner.
+func (u authUser) CanDelete(owner string) bool {
+	return u.Admin || (owner != "" && owner == u.Name)
+}
+
+// loadTokens reads a file of API tokens, one "name token" pair per line.
+// Blank lines and lines starting with # are ignored.
+func loadTokens(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tokens := map[string]string{}
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("bad token line %d", lineNo)
+		}
+		name, token := fields[0], fields[1]
+		if name == adminUser {
+			return nil, fmt.Errorf("reserved name on line %d", lineNo)
+		}
+		tokens[token] = name
+	}
+
+	return tokens, scanner.Err()
+}
+
+// authenticate finds the user for an API or admin token. The tokens are fixed
+// after startup, so this doesn't go through the core. Account logins aren't
+// accepted, because anyone can make an account.
+func (s *server) authenticate(token string) (authUser, bool) {
+	if token == "" {
+		return authUser{}, false
+	}
+	if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+		return authUser{Name: adminUser, Admin: true}, true
+	}
+	for t, name := range s.apiTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return authUser{Name: name}, true
+		}
+	}
+	return authUser{}, false
+}
+
+// authenticateAccount finds the account user for a login token.
+func (s *server) authenticateAccount(token string) (authUser, bool) {
+	if token == "" || s.accounts == nil {
+		return authUser{}, false
+	}
+	name, ok := s.accounts.Lookup(token)
+	if !ok {
+		return authUser{}, false
+	}
+	return authUser{Name: name}, true
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+func TestLoadTokens(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		file := path.Join(dir, "tokens")
+		os.WriteFile(file, []byte(content), 0600)
+		return file
+	}
+
+	tokens, err := loadTokens(write("# comment\n\nphil abc\n  bob def  \n"))
+	if err != nil || len(tokens) != 2 || tokens["abc"] != "phil" || tokens["def"] != "bob" {
+		t.Errorf("bad tokens: %v %v", tokens, err)
+	}
+	if _, err := loadTokens(write("phil\n")); err == nil {
+		t.Errorf("expected error for bad line")
+	}
+	if _, err := loadTokens(write("admin abc\n")); err == nil {
+		t.Errorf("expected error for admin name")
+	}
+	if _, err := loadTokens(path.Join(dir, "nope")); err == nil {
+		t.Errorf("expected error for no file")
+	}
+}
+
+func TestCanDelete(t *testing.T) {
+	phil := authUser{Name: "phil"}
+	if !phil.CanDelete("phil") || phil.CanDelete("bob") || phil.CanDelete("") {
+		t.Errorf("bad owner checks")
+	}
+	admin := authUser{Name: adminUser, Admin: true}
+	if !admin.CanDelete("phil") || !admin.CanDelete("") {
+		t.Errorf("admin can delete anything")
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &server{
+		adminToken: "adm",
+		apiTokens:  map[string]string{"abc": "phil"},
+	}
+	r := gin.New()
+	ok := func(c *gin.Context) {
+		c.String(http.StatusOK, getAuthUser(c).Name)
+	}
+	r.GET("/any", requireAuth(s), ok)
+	r.GET("/admin", requireAuth(s), requireAdmin(), ok)
+
+	get := func(url, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		url, auth string
+		code      int
+		user      string
+	}{
+		{"/any", "", http.StatusUnauthorized, ""},
+		{"/any", "abc", http.StatusUnauthorized, ""},
+		{"/any", "Bearer ", http.StatusUnauthorized, ""},
+		{"/any", "Bearer nope", http.StatusUnauthorized, ""},
+		{"/any", "Bearer abc", http.StatusOK, "phil"},
+		{"/any", "Bearer adm", http.StatusOK, adminUser},
+		{"/admin", "", http.StatusUnauthorized, ""},
+		{"/admin", "Bearer nope", http.StatusUnauthorized, ""},
+		{"/admin", "Bearer abc", http.StatusForbidden, ""},
+		{"/admin", "Bearer adm", http.StatusOK, adminUser},
+	}
+	for _, tt := range tests {
+		w := get(tt.url, tt.auth)
+		if w.Code != tt.code || (tt.code == http.StatusOK && w.Body.String() != tt.user) {
+			t.Errorf("%s %q: expected %d %s, got %d %s", tt.url, tt.auth, tt.code, tt.user, w.Code, w.Body)
+		}
+	}
+
+	// with no admin token set, nothing is admin
+	s.adminToken = ""
+	if w := get("/admin", "Bearer "); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized with no admin token, got %d", w.Code)
+	}
+}
+
+func TestDeleteGame_owner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	inTempDir(t)
+
+	s := &server{
+		adminToken: "adm",
+		apiTokens:  map[string]string{"abc": "phil", "def": "bob"},
+		games:      map[string]*instance{},
+	}
+	runTestCore(s)
+	defer close(s.coreCh)
+
+	for _, id := range []string{"g1", "g2"} {
+		g := newInstance("go", id)
+		g.meta = gameMeta{Owner: "phil"}
+		s.games[id] = g
+	}
+
+	rh := &restHandler{server: s}
+	r := gin.New()
+	r.DELETE("/games/:id", requireAuth(s), rh.deleteGame)
+
+	del := func(id, token string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/games/"+id, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := del("g1", "def"); code != http.StatusForbidden {
+		t.Errorf("expected forbidden for non-owner, got %d", code)
+	}
+	if code := del("g1", "abc"); code != http.StatusOK {
+		t.Errorf("expected ok for owner, got %d", code)
+	}
+	if code := del("g2", "adm"); code != http.StatusOK {
+		t.Errorf("expected ok for admin, got %d", code)
+	}
+	if len(s.games) != 0 {
+		t.Errorf("expected games gone, got %d", len(s.games))
+	}
+}
+
+func TestAccountToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	accounts, err := loadAccounts(path.Join(t.TempDir(), "accounts.json"), []string{"phil"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts.Create("anna", "password1", "Anna")
+	token, _, _ := accounts.Login("anna", "password1")
+
+	s := &server{
+		apiTokens: map[string]string{"abc": "phil"},
+		accounts:  accounts,
+	}
+	r := newWebRouter(s, log.Logger)
+
+	do := func(method, url, token string) int {
+		req := httptest.NewRequest(method, url, strings.NewReader("{}"))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// a login is only for the account's own things
+	for _, url := range []string{"/api/games", "/api/imports", "/api/games/g1/fork", "/api/games/g1/webhooks", "/api/webhooks"} {
+		if code := do(http.MethodPost, url, token); code != http.StatusUnauthorized {
+			t.Errorf("%s: expected unauthorized for account, got %d", url, code)
+		}
+	}
+	if code := do(http.MethodGet, "/api/games/g1/export", token); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized for account export, got %d", code)
+	}
+	if code := do(http.MethodPut, "/api/games/g1/seats/phil", token); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized for account linking a seat, got %d", code)
+	}
+
+	if code := do(http.MethodGet, "/api/me", token); code != http.StatusOK {
+		t.Errorf("expected ok for account, got %d", code)
+	}
+	if code := do(http.MethodGet, "/api/me", "abc"); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized for API token, got %d", code)
+	}
+
+	if code := do(http.MethodPost, "/api/logout", token); code != http.StatusOK {
+		t.Errorf("expected logged out, got %d", code)
+	}
+	if code := do(http.MethodGet, "/api/me", token); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized after logout, got %d", code)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/rs/zerolog/log"
+)
+
+// botKinds are the bots that the server has itself, which can play any game,
+// by the name used in MakePlayerInput.Bot.
+var botKinds = map[string]func() game.Bot{
+	"random": func() game.Bot {
+		return game.NewRandomBot(time.Now().UnixNano())
+	},
+}
+
+// pluginBotKind finds which of a game type's own bots a name is for. They are
+// named after the type, e.g. go-hard is the go plugin's hard bot.
+func pluginBotKind(desc game.Description, name string) (string, bool) {
+	kind := strings.TrimPrefix(name, desc.Name+"-")
+	if kind == name {
+		return "", false
+	}
+	for _, k := range desc.Bots {
+		if k == kind {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+func checkBots(desc game.Description, players []MakePlayerInput) error {
+	for _, pl := range players {
+		if pl.Bot == "" {
+			continue
+		}
+		if _, ok := botKinds[pl.Bot]; ok {
+			continue
+		}
+		if _, ok := pluginBotKind(desc, pl.Bot); !ok {
+			return fmt.Errorf("unknown bot for %s: %s", desc.Name, pl.Bot)
+		}
+	}
+	return nil
+}
+
+// pluginBot asks the game's plugin what to play, so that the game's own bots
+// stay in the game's binary, and are upgraded with it.
+type pluginBot struct {
+	pool   *pluginPool
+	game   string
+	player string
+	kind   string
+}
+
+// Play implements game.Bot.
+func (b *pluginBot) Play(update game.GameUpdate) *game.Command {
+	h := b.pool.hostOf(b.game)
+	if h == nil {
+		// not running, so there'll be an update when it is
+		return nil
+	}
+
+	bs, err := json.Marshal(update)
+	if err != nil {
+		return nil
+	}
+
+	var res *game.RBotResponse
+	err = h.call(func(cli game.InstanceClient) (err error) {
+		res, err = cli.Bot(context.TODO(), &game.RBotRequest{
+			Game:   b.game,
+			Player: b.player,
+			Kind:   b.kind,
+			Update: bs,
+		})
+		return
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("instance", b.game).Str("bot", b.player).Msg("bot rpc failed")
+		return nil
+	}
+	if res.Command == "" {
+		return nil
+	}
+
+	return &game.Command{Command: game.CommandString(res.Command), Options: res.Options}
+}
+
+// startBots starts a goroutine for each bot seat in a game.
+func (s *server) startBots(g *instance) {
+	for name, kindName := range g.meta.Bots {
+		var bot game.Bot
+		if newBot, ok := botKinds[kindName]; ok {
+			bot = newBot()
+		} else if kind, ok := pluginBotKind(s.types[g.gameType], kindName); ok {
+			bot = &pluginBot{g.pool, g.id, name, kind}
+		} else {
+			g.log.Warn().Msgf("unknown bot %s for %s", kindName, name)
+			continue
+		}
+		go s.runBot(g.id, name, bot)
+	}
+}
+
+// maxBotFailures is how many commands in a row can fail before a bot waits
+// for something to change.
+const maxBotFailures = 5
+
+// runBot connects a bot to a seat, as if it were a gateway with a client, so
+// that everything it does goes the same way as for a human.
+func (s *server) runBot(gameId, name string, bot game.Bot) {
+	downCh := make(chan interface{}, 100)
+
+	res := s.connectBot(gameId, name, clientBundle{downCh})
+	if res.Err != nil {
+		return
+	}
+
+	log := log.With().Str("instance", gameId).Str("bot", name).Logger()
+	log.Info().Msg("bot playing")
+
+	reqNo := 0
+	waiting := false
+	// failed commands since the last update
+	failures := 0
+	var latest *game.GameUpdate
+
+	for down := range downCh {
+		msg, err := encodeDown(down)
+		if err != nil {
+			continue
+		}
+
+		f := msg.Head.Fields()
+		switch f[0] {
+		case "update":
+			update := game.GameUpdate{}
+			err := comms.Decode(msg, &update)
+			if err != nil {
+				log.Info().Err(err).Msg("bad update")
+				continue
+			}
+			latest = &update
+			failures = 0
+		case "response":
+			waiting = false
+			res := game.PlayResultJSON{}
+			err := comms.Decode(msg, &res)
+			if err == nil && res.Err == nil {
+				// wait for the update that this causes
+				continue
+			}
+			// something went wrong, so think again, but not forever
+			log.Info().Msgf("bot command failed: %v", res.Err)
+			failures++
+			if failures >= maxBotFailures {
+				log.Warn().Msg("bot stuck, waiting for an update")
+			}
+		default:
+			continue
+		}
+
+		if waiting || latest == nil || failures >= maxBotFailures {
+			continue
+		}
+
+		cmd := bot.Play(*latest)
+		if cmd == nil {
+			continue
+		}
+
+		body, err := json.Marshal(cmd)
+		if err != nil {
+			continue
+		}
+
+		// don't play faster than a human could follow, and slower after
+		// failures
+		time.Sleep(s.botDelay << failures)
+
+		id := "bot" + strconv.Itoa(reqNo)
+		reqNo++
+		waiting = true
+		s.coreCh <- requestFromUser{gameId, name, id, []string{"play"}, body}
+	}
+
+	log.Info().Msg("bot gone")
+}
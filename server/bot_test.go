@@ -0,0 +1,153 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// stubbornBot always wants to do the same thing.
+type stubbornBot struct{}
+
+func (stubbornBot) Play(update game.GameUpdate) *game.Command {
+	return &game.Command{Command: "dicemove"}
+}
+
+func TestConnect_botSeat(t *testing.T) {
+	s := &server{games: map[string]*instance{}}
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Bots: map[string]string{"robo": "random"}}
+	s.games[g.id] = g
+
+	connect := func(name string, bot bool) error {
+		rep := make(chan connectResult, 1)
+		s.doConnect(connectMsg{"g1", name, comms.ConnectRequest{}, clientBundle{make(chan interface{}, 10)}, rep, bot})
+		return (<-rep).Err
+	}
+
+	if err := connect("robo", false); err != errNotAllowed {
+		t.Errorf("expected human kept out of bot seat, got %v", err)
+	}
+	if err := connect("phil", true); err != errNotAllowed {
+		t.Errorf("expected bot kept out of human seat, got %v", err)
+	}
+	if err := connect("robo", true); err != nil {
+		t.Errorf("expected bot in, got %v", err)
+	}
+	if err := connect("phil", false); err != nil {
+		t.Errorf("expected human in, got %v", err)
+	}
+}
+
+func TestRunBot_failures(t *testing.T) {
+	s := &server{coreCh: make(chan interface{}, 10), botDelay: time.Millisecond}
+
+	// a core where every command fails
+	var downCh chan interface{}
+	requests := make(chan string, 100)
+	go func() {
+		for in := range s.coreCh {
+			switch msg := in.(type) {
+			case connectMsg:
+				downCh = msg.Client.downCh
+				msg.Rep <- connectResult{}
+				downCh <- toSend{"update", game.GameUpdate{Playing: "robo"}}
+			case string:
+				// the test wants a new update sent
+				downCh <- toSend{"update", game.GameUpdate{Playing: "robo"}}
+			case requestFromUser:
+				requests <- msg.ID
+				err := comms.WrapError(game.Error(game.StatusNotYourTurn, ""))
+				downCh <- responseToUser{msg.ID, game.PlayResultJSON{Err: err}}
+			}
+		}
+	}()
+
+	go s.runBot("g1", "robo", stubbornBot{})
+
+	count := func() int {
+		n := 0
+		for {
+			select {
+			case <-requests:
+				n++
+			case <-time.After(200 * time.Millisecond):
+				return n
+			}
+		}
+	}
+
+	// it tries a few times, slowing down, then waits
+	if n := count(); n != maxBotFailures {
+		t.Errorf("expected %d tries, got %d", maxBotFailures, n)
+	}
+
+	if n := count(); n != 0 {
+		t.Errorf("expected no more tries, got %d", n)
+	}
+
+	// until something changes
+	s.coreCh <- "update"
+	if n := count(); n != maxBotFailures {
+		t.Errorf("expected %d tries after update, got %d", maxBotFailures, n)
+	}
+}
+
+func TestCheckBots(t *testing.T) {
+	desc := game.Description{Name: "go", Bots: []string{"easy", "hard"}}
+
+	for _, bot := range []string{"", "random", "go-easy", "go-hard"} {
+		if err := checkBots(desc, []MakePlayerInput{{Name: "robo", Bot: bot}}); err != nil {
+			t.Errorf("expected %q allowed, got %v", bot, err)
+		}
+	}
+	for _, bot := range []string{"go-normal", "easy", "rummy-easy", "go-"} {
+		if err := checkBots(desc, []MakePlayerInput{{Name: "robo", Bot: bot}}); err == nil {
+			t.Errorf("expected %q refused", bot)
+		}
+	}
+}
+
+func TestAfterUserRequest_botUpdate(t *testing.T) {
+	s := &server{}
+
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Bots: map[string]string{"robo": "random"}}
+	g.state = &game.RGameState{
+		Players: []*game.RPlayerState{{Name: "phil"}, {Name: "robo"}},
+	}
+	for _, name := range []string{"phil", "robo"} {
+		g.sessions[name] = newSession(false)
+		g.clients[name] = &clientBundle{make(chan interface{}, 10)}
+	}
+
+	heads := func(name string) []string {
+		var out []string
+		for len(g.clients[name].downCh) > 0 {
+			msg, _ := encodeDown(<-g.clients[name].downCh)
+			out = append(out, msg.Type())
+		}
+		return out
+	}
+
+	// a play that works but says nothing still gets the bot an update, to go on
+	s.afterUserRequest(afterRequest{g, "robo", responseToUser{"bot0", game.PlayResultJSON{}}, nil, nil})
+	if h := heads("robo"); len(h) != 2 || h[1] != "update" {
+		t.Errorf("expected response then update, got %v", h)
+	}
+
+	// but not one that failed, as that would reset its backoff
+	err := comms.WrapError(game.Error(game.StatusNotYourTurn, ""))
+	s.afterUserRequest(afterRequest{g, "robo", responseToUser{"bot1", game.PlayResultJSON{Err: err}}, nil, nil})
+	if h := heads("robo"); len(h) != 1 {
+		t.Errorf("expected only response, got %v", h)
+	}
+
+	// and people don't need one
+	s.afterUserRequest(afterRequest{g, "phil", responseToUser{"1", game.PlayResultJSON{}}, nil, nil})
+	if h := heads("phil"); len(h) != 1 {
+		t.Errorf("expected only response, got %v", h)
+	}
+}
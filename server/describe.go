@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/rs/zerolog/log"
+)
+
+// describeTimeout is how long a plugin has to start and describe itself
+const describeTimeout = 10 * time.Second
+
+// describeTypes runs each game type's plugin with no game, just to ask what
+// the type is, and to get its assets. Types that can't say get a plain default
+// description.
+func describeTypes(ctx context.Context, gameTypes []string) (map[string]game.Description, map[string]map[string]*asset) {
+	types := map[string]game.Description{}
+	assets := map[string]map[string]*asset{}
+	for _, gt := range gameTypes {
+		desc, typeAssets, err := describeType(ctx, gt, path.Join("bind", "describe.pipe"))
+		if err != nil {
+			log.Warn().Err(err).Msgf("cannot describe game type: %s", gt)
+			desc = game.Description{
+				DisplayName: gt,
+				MinPlayers:  1,
+				MaxPlayers:  6,
+			}
+		}
+		// the server's name for the type is the one that counts
+		desc.Name = gt
+		types[gt] = desc
+		assets[gt] = typeAssets
+	}
+	return types, assets
+}
+
+func describeType(ctx context.Context, gameType, bind string) (game.Description, map[string]*asset, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+
+	dir := "./" + path.Join("run", gameType)
+
+	pro := newProcess(dir, "./bin", bind)
+	pctx, pcancel := context.WithCancel(ctx)
+	conn, err := pro.Start(pctx)
+	if err != nil {
+		pcancel()
+		return game.Description{}, nil, err
+	}
+	defer func() {
+		conn.Close()
+		pcancel()
+		<-pro.Done()
+	}()
+
+	cli := game.NewInstanceClient(conn)
+	res, err := cli.Describe(ctx, &game.RDescribeRequest{})
+	if err != nil {
+		return game.Description{}, nil, err
+	}
+
+	assets, err := fetchAssets(ctx, cli)
+	if err != nil {
+		log.Warn().Err(err).Msgf("cannot get assets for game type: %s", gameType)
+	}
+
+	return *game.UnwrapDescription(res), assets, nil
+}
+
+// checkMakeGame checks a request to make a game against the description of
+// the game type.
+func checkMakeGame(desc game.Description, in MakeGameInput) error {
+	if n := len(in.Players); n < desc.MinPlayers || n > desc.MaxPlayers {
+		return fmt.Errorf("must have %d-%d players", desc.MinPlayers, desc.MaxPlayers)
+	}
+
+	err := game.ValidateJSON(desc.Options, in.Options)
+	if err != nil {
+		return err
+	}
+
+	for _, pl := range in.Players {
+		err := game.ValidateJSON(desc.PlayerOptions, pl.Options)
+		if err != nil {
+			return fmt.Errorf("player %s: %w", pl.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestCheckMakeGame(t *testing.T) {
+	desc := game.Description{
+		Name:          "test",
+		MinPlayers:    1,
+		MaxPlayers:    2,
+		Options:       json.RawMessage(`{"type":"object","properties":{"goal":{"type":"integer"}},"additionalProperties":false}`),
+		PlayerOptions: json.RawMessage(`{"type":"object","required":["colour"]}`),
+	}
+
+	player := MakePlayerInput{Name: "a", Options: json.RawMessage(`{"colour":"red"}`)}
+
+	tests := []struct {
+		name string
+		in   MakeGameInput
+		ok   bool
+	}{
+		{"good", MakeGameInput{Players: []MakePlayerInput{player}, Options: json.RawMessage(`{"goal":2}`)}, true},
+		{"no options", MakeGameInput{Players: []MakePlayerInput{player}}, true},
+		{"no players", MakeGameInput{}, false},
+		{"too many players", MakeGameInput{Players: []MakePlayerInput{player, player, player}}, false},
+		{"bad options", MakeGameInput{Players: []MakePlayerInput{player}, Options: json.RawMessage(`{"goal":"x"}`)}, false},
+		{"bad player", MakeGameInput{Players: []MakePlayerInput{{Name: "b"}}}, false},
+	}
+
+	for _, tt := range tests {
+		err := checkMakeGame(desc, tt.in)
+		if tt.ok != (err == nil) {
+			t.Errorf("%s: unexpected result: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// bundleFormat is the version of the bundle itself, not of what's in it.
+const bundleFormat = 1
+
+// GameBundle is a whole game, to be moved to another server, or attached to a
+// bug report.
+type GameBundle struct {
+	Format   int       `json:"format"`
+	Type     string    `json:"type"`
+	Version  string    `json:"version"`
+	Game     string    `json:"game"`
+	Exported time.Time `json:"exported"`
+	// Players are the seats, in order
+	Players []string          `json:"players"`
+	Bots    map[string]string `json:"bots,omitempty"`
+	// Save is the plugin's save, which only the plugin understands
+	Save []byte        `json:"save"`
+	News []game.Change `json:"news"`
+}
+
+// check says whether a bundle could be loaded here.
+func (b *GameBundle) check(types map[string]game.Description) error {
+	if b.Format != bundleFormat {
+		return game.Errorf(game.StatusBadRequest, "unknown bundle format: %d", b.Format)
+	}
+	desc, ok := types[b.Type]
+	if !ok {
+		return game.Errorf(game.StatusBadRequest, "unknown game type: %s", b.Type)
+	}
+	if b.Version != desc.Version {
+		return game.Errorf(game.StatusBadRequest, "save is version %q, but %s is version %q", b.Version, b.Type, desc.Version)
+	}
+	if len(b.Save) == 0 {
+		return game.Error(game.StatusBadRequest, "no save")
+	}
+	if len(b.Players) == 0 {
+		return game.Error(game.StatusBadRequest, "no players")
+	}
+	var check []MakePlayerInput
+	for n, name := range b.Players {
+		if name == "" || stringListContains(b.Players[:n], name) {
+			return game.Errorf(game.StatusBadRequest, "bad player: %q", name)
+		}
+		check = append(check, MakePlayerInput{Name: name, Bot: b.Bots[name]})
+	}
+	for seat := range b.Bots {
+		if !stringListContains(b.Players, seat) {
+			return game.Errorf(game.StatusBadRequest, "no such seat: %s", seat)
+		}
+	}
+	if err := checkBots(desc, check); err != nil {
+		return game.Error(game.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+func (s *server) doExportGame(in exportGameMsg) {
+	src, err := s.findSave(in.Game, in.User)
+	if err != nil {
+		in.Rep <- exportGameResult{Err: err}
+		return
+	}
+
+	b := &GameBundle{
+		Format:   bundleFormat,
+		Type:     src.gameType,
+		Version:  s.types[src.gameType].Version,
+		Game:     src.id,
+		Exported: time.Now(),
+		Players:  src.seats,
+		Bots:     src.bots,
+	}
+
+	go func() {
+		var err error
+		b.Save, err = ioutil.ReadFile(src.file)
+		if err != nil {
+			in.Rep <- exportGameResult{Err: err}
+			return
+		}
+		b.News, err = src.news()
+		if err != nil {
+			in.Rep <- exportGameResult{Err: err}
+			return
+		}
+		in.Rep <- exportGameResult{Bundle: b}
+	}()
+}
+
+// doImportGame starts a new game from a bundle.
+func (s *server) doImportGame(in importGameMsg) {
+	b := in.Bundle
+	err := b.check(s.types)
+	if err != nil {
+		in.Rep <- loadGameResult{Err: err}
+		return
+	}
+
+	i := s.newInstance(b.Type, s.newGameID())
+	i.meta = gameMeta{Owner: in.User.Name, Created: time.Now(), Imported: true}
+	if len(b.Bots) > 0 {
+		i.meta.Bots = b.Bots
+	}
+
+	go s.startFromSave(i, b.Save, b.News, b.Players, in.Rep)
+}
+
+func (s *server) ExportGame(id string, user authUser) (*GameBundle, error) {
+	resCh := make(chan exportGameResult)
+	s.coreCh <- exportGameMsg{id, user, resCh}
+	res := <-resCh
+	return res.Bundle, res.Err
+}
+
+func (s *server) ImportGame(bundle *GameBundle, user authUser) (MakeGameOutput, error) {
+	resCh := make(chan loadGameResult)
+	s.coreCh <- importGameMsg{bundle, user, resCh}
+	res := <-resCh
+	return res.Out, res.Err
+}
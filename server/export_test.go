@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestGameBundle_check(t *testing.T) {
+	types := map[string]game.Description{"go": {Name: "go", Version: "1"}}
+	good := func() *GameBundle {
+		return &GameBundle{
+			Format:  bundleFormat,
+			Type:    "go",
+			Version: "1",
+			Players: []string{"a", "b"},
+			Bots:    map[string]string{"b": "random"},
+			Save:    []byte("{}"),
+		}
+	}
+
+	if err := good().check(types); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	bad := map[string]func(b *GameBundle){
+		"format":     func(b *GameBundle) { b.Format = 99 },
+		"type":       func(b *GameBundle) { b.Type = "chess" },
+		"version":    func(b *GameBundle) { b.Version = "2" },
+		"no save":    func(b *GameBundle) { b.Save = nil },
+		"no players": func(b *GameBundle) { b.Players = nil },
+		"same name":  func(b *GameBundle) { b.Players = []string{"a", "a"} },
+		"bot seat":   func(b *GameBundle) { b.Bots = map[string]string{"c": "random"} },
+		"bot kind":   func(b *GameBundle) { b.Bots = map[string]string{"b": "nonsense"} },
+	}
+	for name, f := range bad {
+		b := good()
+		f(b)
+		if err := b.check(types); game.Code(err) != game.StatusBadRequest {
+			t.Errorf("%s: expected bad request, got %v", name, err)
+		}
+	}
+}
+
+func TestCheckLoaded(t *testing.T) {
+	state := func(status game.GameStatus, players ...string) *game.RGameState {
+		s := &game.RGameState{Status: string(status)}
+		for _, name := range players {
+			s.Players = append(s.Players, &game.RPlayerState{Name: name})
+		}
+		return s
+	}
+
+	if err := checkLoaded(state(game.StatusInProgress, "a", "b"), []string{"b", "a"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	bad := map[string]*game.RGameState{
+		"won":          state(game.StatusWon, "a", "b"),
+		"more players": state(game.StatusInProgress, "a", "b", "c"),
+		"less players": state(game.StatusInProgress, "a"),
+		"other player": state(game.StatusInProgress, "a", "c"),
+	}
+	for name, st := range bad {
+		if err := checkLoaded(st, []string{"a", "b"}); game.Code(err) != game.StatusBadRequest {
+			t.Errorf("%s: expected bad request, got %v", name, err)
+		}
+	}
+}
+
+func TestImportGame_tooBig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &server{apiTokens: map[string]string{"abc": "phil"}}
+	r := newWebRouter(s, log.Logger)
+
+	// never gets as far as the core
+	body := `{"save":"` + strings.Repeat("A", maxBundleSize) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/imports", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer abc")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", w.Code)
+	}
+}
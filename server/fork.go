@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ForkGameInput is how a fork differs from the game it's forked from.
+type ForkGameInput struct {
+	// Bots are seats to be played by bots in the fork, mapped to the kind of
+	// bot. Seats that were bots already stay so.
+	Bots map[string]string `json:"bots"`
+}
+
+// saveSource is a game's save, whether the game is live or archived.
+type saveSource struct {
+	id       string
+	gameType string
+	file     string
+	bots     map[string]string
+	seats    []string
+	over     bool
+	archived bool
+}
+
+// news reads the news log of the game. It's not for the core, as it reads
+// files.
+func (src *saveSource) news() ([]game.Change, error) {
+	if !src.archived {
+		return loadNews(src.gameType, src.id)
+	}
+	a, err := loadArchived(src.gameType, src.id)
+	if err != nil {
+		return nil, err
+	}
+	return a.News, nil
+}
+
+// findSave finds a game's save, for a user who owns it. Archived games are
+// mostly ones that were won, which can't be forked, so an archived save is
+// only any use if an admin ended the game early.
+func (s *server) findSave(id string, user authUser) (*saveSource, error) {
+	if g, ok := s.games[id]; ok {
+		if !user.CanDelete(g.meta.Owner) {
+			return nil, errNotAllowed
+		}
+		if g.state == nil {
+			return nil, game.Error(game.StatusConflict, "game is not running")
+		}
+		src := &saveSource{
+			id:       g.id,
+			gameType: g.gameType,
+			file:     g.SaveFile(),
+			bots:     g.meta.Bots,
+			over:     g.state.Status == string(game.StatusWon),
+		}
+		for _, pl := range g.state.Players {
+			src.seats = append(src.seats, pl.Name)
+		}
+		return src, nil
+	}
+
+	if a, ok := s.archive[id]; ok {
+		if !user.CanDelete(a.Owner) {
+			return nil, errNotAllowed
+		}
+		archived, err := loadArchived(a.Type, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &saveSource{
+			id:       a.ID,
+			gameType: a.Type,
+			file:     archiveSaveFileName(a.Type, a.ID),
+			bots:     archived.Bots,
+			seats:    a.Players,
+			over:     a.Winner != "",
+			archived: true,
+		}, nil
+	}
+
+	return nil, errGameNotFound
+}
+
+// doForkGame makes a new game from a copy of another's save, with fresh
+// connect codes, so that a position can be played again differently.
+func (s *server) doForkGame(in forkGameMsg) {
+	src, err := s.findSave(in.Game, in.User)
+	if err == nil && src.over {
+		err = game.Error(game.StatusBadRequest, "game is over")
+	}
+	if err != nil {
+		in.Rep <- loadGameResult{Err: err}
+		return
+	}
+
+	bots := map[string]string{}
+	for seat, kind := range src.bots {
+		bots[seat] = kind
+	}
+	var check []MakePlayerInput
+	for seat, kind := range in.Req.Bots {
+		if !stringListContains(src.seats, seat) {
+			in.Rep <- loadGameResult{Err: game.Errorf(game.StatusBadRequest, "no such seat: %s", seat)}
+			return
+		}
+		check = append(check, MakePlayerInput{Name: seat, Bot: kind})
+		bots[seat] = kind
+	}
+	if err := checkBots(s.types[src.gameType], check); err != nil {
+		in.Rep <- loadGameResult{Err: game.Error(game.StatusBadRequest, err.Error())}
+		return
+	}
+
+	i := s.newInstance(src.gameType, s.newGameID())
+	i.meta = gameMeta{Owner: in.User.Name, Created: time.Now(), ForkedFrom: in.Game}
+	if len(bots) > 0 {
+		i.meta.Bots = bots
+	}
+
+	go func() {
+		save, err := ioutil.ReadFile(src.file)
+		if err != nil {
+			in.Rep <- loadGameResult{Err: err}
+			s.coreCh <- releaseIDMsg{i.id}
+			return
+		}
+		s.startFromSave(i, save, nil, src.seats, in.Rep)
+	}()
+}
+
+// startFromSave starts a new game on a save from somewhere else, e.g. another
+// game, and puts it into the core as if it had been created. The game's id
+// must have been reserved. This is not for the core, as it waits for the
+// plugin.
+func (s *server) startFromSave(i *instance, save []byte, news []game.Change, seats []string, rep chan loadGameResult) {
+	ctx := context.TODO()
+
+	err := ioutil.WriteFile(i.SaveFile(), save, 0644)
+	if err == nil {
+		err = i.StartLoad(ctx)
+	}
+	if err == nil {
+		err = checkLoaded(i.state, seats)
+	}
+	if err != nil {
+		log.Err(err).Msgf("instance load failed: %s", i.id)
+		rep <- loadGameResult{Err: err}
+		err := i.Shutdown()
+		if err != nil {
+			log.Err(err).Msgf("instance shutdown failed: %s", i.id)
+		}
+		// the id is reserved, so the save is this game's own
+		os.Remove(i.SaveFile())
+		s.coreCh <- releaseIDMsg{i.id}
+		return
+	}
+
+	err = saveMeta(i.gameType, i.id, i.meta)
+	if err != nil {
+		log.Err(err).Msgf("instance meta save failed: %s", i.id)
+	}
+	if len(news) > 0 {
+		err = appendNews(i.gameType, i.id, news)
+		if err != nil {
+			log.Err(err).Msgf("instance news save failed: %s", i.id)
+		}
+	}
+
+	players := map[string]string{}
+	for _, seat := range seats {
+		if i.meta.Bots[seat] != "" {
+			continue
+		}
+		players[seat] = encodeConnectString(i.id, seat)
+	}
+
+	out := MakeGameOutput{Type: i.gameType, ID: i.id, Owner: i.meta.Owner, Players: players}
+
+	// from here it's the same as a new game
+	createRep := make(chan MakeGameOutput, 1)
+	s.coreCh <- afterCreate{createGameMsg{Owner: i.meta.Owner, Rep: createRep}, out, i}
+	rep <- loadGameResult{Out: <-createRep}
+}
+
+// checkLoaded checks that a save, once loaded, is a game that can go on, with
+// the seats that it was said to have.
+func checkLoaded(state *game.RGameState, seats []string) error {
+	if state.Status == string(game.StatusWon) {
+		return game.Error(game.StatusBadRequest, "game is over")
+	}
+	if len(state.Players) != len(seats) {
+		return game.Errorf(game.StatusBadRequest, "save has %d players, not %d", len(state.Players), len(seats))
+	}
+	for _, pl := range state.Players {
+		if !stringListContains(seats, pl.Name) {
+			return game.Errorf(game.StatusBadRequest, "save has player %s", pl.Name)
+		}
+	}
+	return nil
+}
+
+func (s *server) ForkGame(id string, user authUser, req ForkGameInput) (MakeGameOutput, error) {
+	resCh := make(chan loadGameResult)
+	s.coreCh <- forkGameMsg{id, user, req, resCh}
+	res := <-resCh
+	return res.Out, res.Err
+}
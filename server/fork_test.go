@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestForkGame_checks(t *testing.T) {
+	inTempDir(t)
+
+	s := &server{
+		games:   map[string]*instance{},
+		archive: map[string]ArchiveSummary{},
+	}
+
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Owner: "phil"}
+	g.state = &game.RGameState{
+		Status:  string(game.StatusInProgress),
+		Players: []*game.RPlayerState{{Name: "a"}, {Name: "b"}},
+	}
+	s.games[g.id] = g
+	old := ArchivedGame{ArchiveSummary: ArchiveSummary{ID: "old", Type: "go", Owner: "phil", Winner: "a"}}
+	os.MkdirAll(archiveDir("go"), 0755)
+	data, _ := json.Marshal(old)
+	ioutil.WriteFile(archiveFileName("go", "old"), data, 0644)
+	s.archive["old"] = old.ArchiveSummary
+
+	fork := func(id string, user authUser, req ForkGameInput) error {
+		resCh := make(chan loadGameResult, 1)
+		s.doForkGame(forkGameMsg{id, user, req, resCh})
+		return (<-resCh).Err
+	}
+
+	phil := authUser{Name: "phil"}
+	if err := fork("nope", phil, ForkGameInput{}); err != errGameNotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if err := fork("g1", authUser{Name: "bob"}, ForkGameInput{}); err != errNotAllowed {
+		t.Errorf("expected not allowed, got %v", err)
+	}
+	if err := fork("g1", phil, ForkGameInput{Bots: map[string]string{"c": "random"}}); game.Code(err) != game.StatusBadRequest {
+		t.Errorf("expected bad seat, got %v", err)
+	}
+	if err := fork("g1", phil, ForkGameInput{Bots: map[string]string{"b": "nonsense"}}); game.Code(err) != game.StatusBadRequest {
+		t.Errorf("expected bad bot, got %v", err)
+	}
+	if err := fork("old", phil, ForkGameInput{}); game.Code(err) != game.StatusBadRequest {
+		t.Errorf("expected game over, got %v", err)
+	}
+}
+
+func TestNewGameID(t *testing.T) {
+	s := &server{
+		games:   map[string]*instance{},
+		archive: map[string]ArchiveSummary{},
+	}
+
+	// the first id that comes up is taken, by a live game, then an archived one
+	rand.Seed(1)
+	taken := RandomString(6)
+	s.games[taken] = newInstance("go", taken)
+	rand.Seed(1)
+	if id := s.newGameID(); id == taken {
+		t.Errorf("live game's id given again")
+	}
+	delete(s.games, taken)
+	s.archive[taken] = ArchiveSummary{ID: taken}
+	rand.Seed(1)
+	if id := s.newGameID(); id == taken {
+		t.Errorf("archived game's id given again")
+	}
+	s.reserved = nil
+
+	seen := map[string]bool{}
+	for n := 0; n < 100; n++ {
+		id := s.newGameID()
+		if seen[id] {
+			t.Fatalf("id given twice: %s", id)
+		}
+		seen[id] = true
+	}
+	if len(s.reserved) != 100 {
+		t.Errorf("expected ids held, got %d", len(s.reserved))
+	}
+}
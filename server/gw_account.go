@@ -0,0 +1,155 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// requireAccounts makes a middleware that 404s if accounts are not enabled.
+func requireAccounts(server *server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if server.accounts == nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireAccount makes a middleware that only lets through users who logged in
+// to an account. It's instead of requireAuth, because a login is only good for
+// the account's own things.
+func requireAccount(server *server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		user, ok := server.authenticateAccount(token)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set(authUserKey, user)
+		c.Next()
+	}
+}
+
+type accountHandler struct {
+	server *server
+	log    zerolog.Logger
+}
+
+type createAccountInput struct {
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	DisplayName string `json:"displayName"`
+}
+
+type loginInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginOutput struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+type linkSeatInput struct {
+	Account string `json:"account"`
+}
+
+func (ah *accountHandler) createAccount(c *gin.Context) {
+	i := createAccountInput{}
+	if err := c.BindJSON(&i); err != nil {
+		return
+	}
+
+	info, err := ah.server.accounts.Create(i.Username, i.Password, i.DisplayName)
+	switch err {
+	case nil:
+	case errBadUsername, errBadPassword:
+		c.String(http.StatusBadRequest, "error: %v", err)
+		return
+	case errAccountExists:
+		c.String(http.StatusConflict, "error: %v", err)
+		return
+	default:
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, info)
+}
+
+func (ah *accountHandler) login(c *gin.Context) {
+	i := loginInput{}
+	if err := c.BindJSON(&i); err != nil {
+		return
+	}
+
+	token, expires, err := ah.server.accounts.Login(i.Username, i.Password)
+	if err == errBadLogin {
+		c.String(http.StatusUnauthorized, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, loginOutput{token, expires})
+}
+
+func (ah *accountHandler) logout(c *gin.Context) {
+	token, _ := bearerToken(c)
+	ah.server.accounts.Logout(token)
+	c.String(http.StatusOK, "ok")
+}
+
+func (ah *accountHandler) getMe(c *gin.Context) {
+	info, ok := ah.server.accounts.Get(getAuthUser(c).Name)
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+func (ah *accountHandler) getMyGames(c *gin.Context) {
+	list := ah.server.MyGames(getAuthUser(c).Name)
+	c.JSON(http.StatusOK, list)
+}
+
+func (ah *accountHandler) linkSeat(c *gin.Context) {
+	i := linkSeatInput{}
+	if err := c.BindJSON(&i); err != nil {
+		return
+	}
+
+	err := ah.server.LinkSeat(c.Param("id"), c.Param("seat"), i.Account, getAuthUser(c))
+	switch err {
+	case nil:
+	case errGameNotFound:
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	case errNotAllowed:
+		c.String(http.StatusForbidden, "error: %v", err)
+		return
+	case errSeatTaken:
+		c.String(http.StatusConflict, "error: %v", err)
+		return
+	case errNoSeat, errNoAccount:
+		c.String(http.StatusBadRequest, "error: %v", err)
+		return
+	default:
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.String(http.StatusOK, "ok")
+}
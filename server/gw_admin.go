@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// authUserKey is where the authenticated user is kept in the gin context.
+const authUserKey = "user"
+
+// requireAuth makes a middleware that only lets through requests with a known
+// bearer token, and records who made them.
+func requireAuth(server *server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		user, ok := server.authenticate(token)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set(authUserKey, user)
+		c.Next()
+	}
+}
+
+// bearerToken gets the token from the Authorization header.
+func bearerToken(c *gin.Context) (string, bool) {
+	auth := c.GetHeader("Authorization")
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return token, token != auth
+}
+
+// requireAdmin makes a middleware that only lets through admins. It must come
+// after requireAuth.
+func requireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !getAuthUser(c).Admin {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+func getAuthUser(c *gin.Context) authUser {
+	user, _ := c.Get(authUserKey)
+	u, _ := user.(authUser)
+	return u
+}
+
+type adminHandler struct {
+	server *server
+	log    zerolog.Logger
+}
+
+func (ah *adminHandler) getInstances(c *gin.Context) {
+	list := ah.server.AdminListInstances()
+	c.JSON(http.StatusOK, list)
+}
+
+func (ah *adminHandler) restartInstance(c *gin.Context) {
+	id := c.Param("id")
+
+	err := ah.server.AdminRestart(id)
+	if err == errGameNotFound {
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.String(http.StatusOK, "ok: %s", id)
+}
+
+func (ah *adminHandler) upgradeType(c *gin.Context) {
+	gameType := c.Param("type")
+
+	err := ah.server.AdminUpgrade(gameType)
+	if err == errTypeNotFound {
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	} else if game.Code(err) == game.StatusConflict {
+		c.String(http.StatusConflict, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.String(http.StatusOK, "ok: %s", gameType)
+}
+
+func (ah *adminHandler) getSave(c *gin.Context) {
+	id := c.Param("id")
+
+	data, err := ah.server.AdminDumpSave(id)
+	if err == errGameNotFound {
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+func (ah *adminHandler) endInstance(c *gin.Context) {
+	id := c.Param("id")
+	archive := c.Query("archive") == "true"
+
+	err := ah.server.AdminEnd(id, archive)
+	if err == errGameNotFound {
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+
+	c.String(http.StatusOK, "ok: %s", id)
+}
+
+func (ah *adminHandler) kickClient(c *gin.Context) {
+	id := c.Param("id")
+	name := c.Param("name")
+
+	err := ah.server.AdminKick(id, name)
+	if err == errGameNotFound {
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusBadRequest, "error: %v", err)
+		return
+	}
+
+	c.String(http.StatusOK, "ok: %s", name)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (rh *restHandler) getArchive(c *gin.Context) {
+	list := rh.server.ListArchive(c.Query("type"))
+	c.JSON(http.StatusOK, list)
+}
+
+func (rh *restHandler) getArchived(c *gin.Context) {
+	a, err := rh.server.GetArchived(c.Param("id"))
+	if err == errNotArchived {
+		c.String(http.StatusNotFound, "error: %v", err)
+		return
+	} else if err != nil {
+		c.String(http.StatusInternalServerError, "error: %v", err)
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// asset finds a file from a game type's plugin.
+func (s *server) asset(gameType, name string) (*asset, bool) {
+	s.assetsLock.RLock()
+	defer s.assetsLock.RUnlock()
+	a, ok := s.assets[gameType][name]
+	return a, ok
+}
+
+// getPlayFile serves the web client of a game type, from the files that the
+// plugin has, or else from run/<type>.
+func (rh *restHandler) getPlayFile(c *gin.Context) {
+	gameType := c.Param("type")
+	if !stringListContains(rh.server.gameTypes, gameType) {
+		c.String(http.StatusNotFound, "unknown game type")
+		return
+	}
+
+	rest := c.Param("any")
+	for _, part := range strings.Split(rest, "/") {
+		if part == ".." {
+			c.String(http.StatusBadRequest, "bad path")
+			return
+		}
+	}
+
+	var name string
+	switch {
+	case strings.HasSuffix(rest, "/"):
+		if c.Query("c") == "" {
+			// home page
+			name = "web/home.html"
+		} else {
+			// game page
+			name = "web/index.html"
+		}
+	case strings.HasSuffix(rest, "/data.json"):
+		name = "data.json"
+	default:
+		name = "web" + path.Clean(rest)
+	}
+
+	a, ok := rh.server.asset(gameType, name)
+	if !ok {
+		// plugins without assets have them on disk
+		c.File(path.Join(".", "run", gameType, name))
+		return
+	}
+
+	c.Header("ETag", a.etag)
+	c.Header("Cache-Control", "no-cache")
+	if c.GetHeader("If-None-Match") == a.etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, a.contentType, a.data)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetPlayFile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &server{
+		gameTypes: []string{"go"},
+		assets: map[string]map[string]*asset{
+			"go": {
+				"web/home.html":  newAsset("web/home.html", []byte("<html>home</html>")),
+				"web/index.html": newAsset("web/index.html", []byte("<html>game</html>")),
+				"web/css/go.css": newAsset("web/css/go.css", []byte("body {}")),
+				"data.json":      newAsset("data.json", []byte("{}")),
+			},
+		},
+	}
+	rh := &restHandler{server: s}
+	r := gin.New()
+	r.GET("/play/:type/*any", rh.getPlayFile)
+
+	get := func(url, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/play/go/", "")
+	if w.Code != 200 || w.Body.String() != "<html>home</html>" {
+		t.Errorf("bad home: %d %s", w.Code, w.Body)
+	}
+	w = get("/play/go/?c=abc", "")
+	if w.Code != 200 || w.Body.String() != "<html>game</html>" {
+		t.Errorf("bad index: %d %s", w.Code, w.Body)
+	}
+
+	w = get("/play/go/css/go.css", "")
+	if w.Code != 200 || w.Header().Get("Content-Type") != "text/css; charset=utf-8" {
+		t.Errorf("bad css: %d %s", w.Code, w.Header())
+	}
+	etag := w.Header().Get("ETag")
+	if w = get("/play/go/css/go.css", etag); w.Code != http.StatusNotModified {
+		t.Errorf("expected not modified, got %d", w.Code)
+	}
+
+	if w = get("/play/go/x/data.json", ""); w.Code != 200 || w.Body.String() != "{}" {
+		t.Errorf("bad data: %d %s", w.Code, w.Body)
+	}
+
+	if w = get("/play/go/css/../../../etc/passwd", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", w.Code)
+	}
+	if w = get("/play/chess/", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected not found, got %d", w.Code)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	"github.com/undeconstructed/gogogo/api"
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// codeMetadata is where gRPC calls give their connect code.
+const codeMetadata = "x-gogogo-code"
+
+func runGRPCGateway(ctx context.Context, server *server, addr string) error {
+	log := log.With().Str("gw", "grpc").Logger()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	var opts []grpc.ServerOption
+	if server.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(server.tlsConfig)))
+	}
+	gs := grpc.NewServer(opts...)
+	api.RegisterGameServer(gs, &grpcHandler{server: server, log: log})
+
+	log.Info().Msgf("grpc listening on %v", ln.Addr())
+
+	go func() {
+		err := gs.Serve(ln)
+		log.Info().Err(err).Msg("server return")
+	}()
+	go func() {
+		<-ctx.Done()
+		gs.Stop()
+	}()
+
+	return nil
+}
+
+// grpcHandler is the public gRPC API, which is another way for players to
+// do what they can do over comms.
+type grpcHandler struct {
+	api.UnimplementedGameServer
+
+	server *server
+	log    zerolog.Logger
+}
+
+// player finds who a call is from, by connect code.
+func (gh *grpcHandler) player(ctx context.Context) (string, string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	vals := md.Get(codeMetadata)
+	if len(vals) != 1 {
+		return "", "", status.Error(codes.Unauthenticated, "missing connect code")
+	}
+	gameId, playerId, err := decodeConnectString(vals[0])
+	if err != nil {
+		return "", "", status.Error(codes.Unauthenticated, "bad connect code")
+	}
+	return gameId, playerId, nil
+}
+
+// grpcError turns server errors into gRPC ones.
+func grpcError(err error) error {
+	switch err {
+	case errGameNotFound, errNoSeat:
+		return status.Error(codes.NotFound, err.Error())
+	case errNotAllowed:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// grpcGameError turns errors from the game into gRPC ones, with the game's
+// code in the details.
+func grpcGameError(cerr *comms.CommsError) error {
+	err := game.ReError(cerr)
+	st := status.Convert(game.ErrorToGRPC(err))
+	out, derr := status.New(st.Code(), st.Message()).WithDetails(&api.ErrorDetail{Code: cerr.Code})
+	if derr != nil {
+		return st.Err()
+	}
+	return out.Err()
+}
+
+func (gh *grpcHandler) Start(ctx context.Context, req *api.StartRequest) (*api.StartResponse, error) {
+	gameId, playerId, err := gh.player(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := gh.server.PlayRequest(gameId, playerId, []string{"start"}, nil)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if r, ok := res.(game.StartResultJSON); ok && r.Err != nil {
+		return nil, grpcGameError(r.Err)
+	}
+
+	return &api.StartResponse{}, nil
+}
+
+func (gh *grpcHandler) Play(ctx context.Context, req *api.PlayRequest) (*api.PlayResponse, error) {
+	gameId, playerId, err := gh.player(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, _ := json.Marshal(game.Command{
+		Command: game.CommandString(req.Command),
+		Options: req.Options,
+	})
+
+	res, err := gh.server.PlayRequest(gameId, playerId, []string{"play"}, body)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	r, ok := res.(game.PlayResultJSON)
+	if !ok {
+		return nil, status.Error(codes.Internal, "bad play result")
+	}
+	if r.Err != nil {
+		return nil, grpcGameError(r.Err)
+	}
+
+	return &api.PlayResponse{Message: r.Msg}, nil
+}
+
+func (gh *grpcHandler) Query(ctx context.Context, req *api.QueryRequest) (*api.QueryResponse, error) {
+	gameId, playerId, err := gh.player(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := gh.server.PlayerState(gameId, playerId)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &api.QueryResponse{State: wrapUpdate(update)}, nil
+}
+
+func (gh *grpcHandler) Subscribe(req *api.SubscribeRequest, stream api.Game_SubscribeServer) error {
+	ctx := stream.Context()
+	gameId, playerId, err := gh.player(ctx)
+	if err != nil {
+		return err
+	}
+
+	log := gh.log.With().Str("game", gameId).Str("player", playerId).Logger()
+	log.Info().Msg("subscribing")
+
+	server := gh.server
+
+	downCh := make(chan interface{}, 100)
+
+	res := server.Watch(gameId, playerId, comms.ConnectRequest{Resume: req.Resume, LastSeq: req.LastSeq}, clientBundle{downCh})
+	if res.Err != nil {
+		return grpcError(res.Err)
+	}
+	defer func() {
+		server.coreCh <- unwatchMsg{gameId, playerId, clientBundle{downCh}}
+	}()
+
+	err = stream.Send(&api.Event{Event: &api.Event_Connected{Connected: &api.Connected{
+		Game:    gameId,
+		Player:  playerId,
+		Resume:  res.Token,
+		Resumed: res.Resumed,
+	}}})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case down, ok := <-downCh:
+			if !ok {
+				// server wants us gone
+				return status.Error(codes.Aborted, "game ended")
+			}
+			msg, err := encodeDown(down)
+			if err != nil {
+				log.Info().Err(err).Msg("encode error")
+				return status.Error(codes.Internal, err.Error())
+			}
+			ev, err := wrapEvent(msg)
+			if err != nil {
+				log.Info().Err(err).Msg("decode error")
+				return status.Error(codes.Internal, err.Error())
+			}
+			if ev == nil {
+				continue
+			}
+			err = stream.Send(ev)
+			if err != nil {
+				log.Info().Err(err).Msg("send error")
+				return err
+			}
+		case <-ctx.Done():
+			log.Info().Msg("subscriber gone")
+			return nil
+		}
+	}
+}
+
+// wrapEvent turns a message into an event, or nil if it's not for subscribers.
+func wrapEvent(msg comms.Message) (*api.Event, error) {
+	switch msg.Type() {
+	case "update":
+		update := game.GameUpdate{}
+		err := comms.Decode(msg, &update)
+		if err != nil {
+			return nil, err
+		}
+		return &api.Event{Seq: msg.Seq, Event: &api.Event_Update{Update: wrapUpdate(&update)}}, nil
+	case "chat":
+		chat := chatMessage{}
+		err := comms.Decode(msg, &chat)
+		if err != nil {
+			return nil, err
+		}
+		return &api.Event{Seq: msg.Seq, Event: &api.Event_Chat{Chat: &api.Chat{Who: chat.Who, Text: chat.Text}}}, nil
+	}
+	return nil, nil
+}
+
+func wrapUpdate(in *game.GameUpdate) *api.GameUpdate {
+	out := &api.GameUpdate{
+		Status:     string(in.Status),
+		Playing:    in.Playing,
+		Winner:     in.Winner,
+		TurnNumber: int32(in.TurnNumber),
+		Global:     in.Global,
+		Private:    in.Private,
+	}
+	for _, n := range in.News {
+		out.News = append(out.News, &api.Change{Who: n.Who, What: n.What, Where: n.Where, Code: n.Code, Params: game.WrapParams(n.Params)})
+	}
+	for _, p := range in.Players {
+		out.Players = append(out.Players, &api.Presence{Name: p.Name, Connected: p.Connected})
+	}
+	if in.Turn != nil {
+		out.Turn = &api.TurnState{
+			Number: int32(in.Turn.Number),
+			Can:    in.Turn.Can,
+			Must:   in.Turn.Must,
+		}
+		out.Turn.Custom, _ = json.Marshal(in.Turn.Custom)
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/api"
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCHandler_player(t *testing.T) {
+	gh := &grpcHandler{}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(codeMetadata, encodeConnectString("g1", "phil")))
+	gameId, playerId, err := gh.player(ctx)
+	if err != nil || gameId != "g1" || playerId != "phil" {
+		t.Errorf("bad player: %s %s %v", gameId, playerId, err)
+	}
+
+	_, _, err = gh.player(context.Background())
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated, got %v", err)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(codeMetadata, "nonsense"))
+	_, _, err = gh.player(ctx)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated, got %v", err)
+	}
+}
+
+func TestWrapUpdate(t *testing.T) {
+	out := wrapUpdate(&game.GameUpdate{
+		News:       []game.Change{{Who: "phil", What: "moves"}},
+		Status:     game.StatusInProgress,
+		Playing:    "phil",
+		TurnNumber: 3,
+		Players:    []game.Presence{{Name: "phil", Connected: true}},
+		Global:     json.RawMessage(`{"goal":1}`),
+		Turn:       &game.TurnState{Number: 1, Can: []string{"dicemove"}, Custom: map[string]int{"x": 1}},
+	})
+
+	if out.Status != "inprogress" || out.TurnNumber != 3 || len(out.News) != 1 || out.News[0].What != "moves" {
+		t.Errorf("bad update: %v", out)
+	}
+	if !out.Players[0].Connected || string(out.Global) != `{"goal":1}` {
+		t.Errorf("bad update: %v", out)
+	}
+	if out.Turn.Can[0] != "dicemove" || string(out.Turn.Custom) != `{"x":1}` {
+		t.Errorf("bad turn: %v", out.Turn)
+	}
+}
+
+func TestGRPCGameError(t *testing.T) {
+	err := grpcGameError(comms.WrapError(game.Error(game.StatusNotYourTurn, "")))
+	st := status.Convert(err)
+	if st.Code() != codes.FailedPrecondition || st.Message() != "it's not your turn" {
+		t.Errorf("bad status: %v", st)
+	}
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("bad details: %v", details)
+	}
+	if d, ok := details[0].(*api.ErrorDetail); !ok || d.Code != "NOTYOURTURN" {
+		t.Errorf("bad detail: %v", details[0])
+	}
+}
+
+func TestWrapEvent(t *testing.T) {
+	msg, _ := comms.Encode("update", game.GameUpdate{Playing: "phil"})
+	msg.Seq = 4
+	ev, err := wrapEvent(msg)
+	if err != nil || ev.Seq != 4 || ev.GetUpdate().GetPlaying() != "phil" {
+		t.Errorf("bad update event: %v %v", ev, err)
+	}
+
+	msg, _ = comms.Encode("chat", chatMessage{"phil", "hi"})
+	ev, err = wrapEvent(msg)
+	if err != nil || ev.GetChat().GetWho() != "phil" || ev.GetChat().GetText() != "hi" {
+		t.Errorf("bad chat event: %v %v", ev, err)
+	}
+
+	msg, _ = comms.Encode("response:1", nil)
+	if ev, err := wrapEvent(msg); ev != nil || err != nil {
+		t.Errorf("expected nothing, got %v %v", ev, err)
+	}
+}
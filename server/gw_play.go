@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// codeHeader is where REST play requests give their connect code.
+const codeHeader = "X-Gogogo-Code"
+
+// playerCode reads the connect code, which must be for the game in the path.
+func playerCode(c *gin.Context) (string, string, bool) {
+	gameId, playerId, err := decodeConnectString(c.GetHeader(codeHeader))
+	if err != nil || gameId != c.Param("id") {
+		c.String(http.StatusUnauthorized, "bad connect code")
+		return "", "", false
+	}
+	return gameId, playerId, true
+}
+
+// maxCommandSize is the biggest command that can be played, the same as over
+// the comms port.
+const maxCommandSize = comms.DefaultMaxSize
+
+func playError(c *gin.Context, err error) {
+	switch err {
+	case errNotAllowed:
+		c.String(http.StatusForbidden, "error: %v", err)
+	case errGameNotFound, errNoSeat:
+		c.String(http.StatusNotFound, "error: %v", err)
+	default:
+		c.String(http.StatusInternalServerError, "error: %v", err)
+	}
+}
+
+func (rh *restHandler) playStart(c *gin.Context) {
+	gameId, playerId, ok := playerCode(c)
+	if !ok {
+		return
+	}
+
+	res, err := rh.server.PlayRequest(gameId, playerId, []string{"start"}, nil)
+	if err != nil {
+		playError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if r, ok := res.(game.StartResultJSON); ok && r.Err != nil {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, res)
+}
+
+func (rh *restHandler) playPlay(c *gin.Context) {
+	gameId, playerId, ok := playerCode(c)
+	if !ok {
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCommandSize))
+	if err != nil {
+		c.String(http.StatusBadRequest, "bad command: %v", err)
+		return
+	}
+	if err := json.Unmarshal(body, &game.Command{}); err != nil {
+		c.String(http.StatusBadRequest, "bad command: %v", err)
+		return
+	}
+
+	res, err := rh.server.PlayRequest(gameId, playerId, []string{"play"}, body)
+	if err != nil {
+		playError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if r, ok := res.(game.PlayResultJSON); ok && r.Err != nil {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, res)
+}
+
+func (rh *restHandler) playState(c *gin.Context) {
+	gameId, playerId, ok := playerCode(c)
+	if !ok {
+		return
+	}
+
+	update, err := rh.server.PlayerState(gameId, playerId)
+	if err != nil {
+		playError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseSince reads the since query param, which is a date or a time, or
+// nothing for all time.
+func parseSince(c *gin.Context) (time.Time, bool) {
+	since := c.Query("since")
+	if since == "" {
+		return time.Time{}, true
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, since); err == nil {
+			return t, true
+		}
+	}
+	c.String(http.StatusBadRequest, "bad since")
+	return time.Time{}, false
+}
+
+func (rh *restHandler) getLeaderboard(c *gin.Context) {
+	since, ok := parseSince(c)
+	if !ok {
+		return
+	}
+	list := rh.server.Leaderboard(c.Param("type"), since)
+	c.JSON(http.StatusOK, list)
+}
+
+func (rh *restHandler) getHistory(c *gin.Context) {
+	gameType := c.Query("type")
+	if gameType == "" {
+		c.String(http.StatusBadRequest, "missing type")
+		return
+	}
+	since, ok := parseSince(c)
+	if !ok {
+		return
+	}
+	list := rh.server.History(gameType, c.Param("name"), since)
+	c.JSON(http.StatusOK, list)
+}
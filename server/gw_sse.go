@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/undeconstructed/gogogo/comms"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveSSE streams a player's messages as server-sent events. It's read-only,
+// so it's for watching a game, through proxies that don't like websockets, or
+// from scripts. It watches the player's session, so whoever is playing the
+// seat stays connected.
+func (ch *commsHandler) serveSSE(c *gin.Context) {
+	addr := c.Request.RemoteAddr
+
+	log := ch.log.With().Str("client", addr).Logger()
+	log.Info().Msgf("connecting events")
+
+	gameId, playerId, err := decodeConnectString(c.Query("c"))
+	if err != nil || gameId != c.Param("id") {
+		c.String(http.StatusBadRequest, "bad connect code")
+		return
+	}
+
+	// resume details are the last event id, which browsers send by themselves
+	// when they reconnect, or in the query, as for websockets
+	req := comms.ConnectRequest{Resume: c.Query("resume")}
+	seq := c.Query("seq")
+	if last := c.GetHeader("Last-Event-ID"); last != "" {
+		ss := strings.SplitN(last, ".", 2)
+		if len(ss) == 2 {
+			req.Resume, seq = ss[0], ss[1]
+		}
+	}
+	if seq != "" {
+		lastSeq, err := strconv.ParseUint(seq, 10, 32)
+		if err != nil {
+			c.String(http.StatusBadRequest, "bad seq")
+			return
+		}
+		req.LastSeq = uint32(lastSeq)
+	}
+
+	server := ch.server
+
+	downCh := make(chan interface{}, 100)
+
+	res := server.Watch(gameId, playerId, req, clientBundle{downCh})
+	switch res.Err {
+	case nil:
+	case errNotAllowed:
+		c.String(http.StatusForbidden, "error: %v", res.Err)
+		return
+	case errNoSeat:
+		c.String(http.StatusBadRequest, "bad connect code")
+		return
+	case errGameNotFound:
+		c.String(http.StatusNotFound, "error: %v", res.Err)
+		return
+	default:
+		log.Info().Err(res.Err).Msgf("connection error, refusing")
+		c.String(http.StatusInternalServerError, "error: %v", res.Err)
+		return
+	}
+	defer func() {
+		server.coreCh <- unwatchMsg{gameId, playerId, clientBundle{downCh}}
+	}()
+
+	// the stream lasts much longer than the server's write timeout
+	if raw, ok := c.Request.Context().Value(rawWriterKey{}).(http.ResponseWriter); ok {
+		err := http.NewResponseController(raw).SetWriteDeadline(time.Time{})
+		if err != nil {
+			log.Warn().Err(err).Msg("cannot clear write deadline")
+		}
+	}
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// for nginx, which buffers otherwise
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	msg, _ := comms.Encode("connected", comms.ConnectResponse{
+		GameID:   gameId,
+		PlayerID: playerId,
+		Resume:   res.Token,
+		Resumed:  res.Resumed,
+	})
+	err = writeSSE(w, res.Token, msg)
+	if err != nil {
+		return
+	}
+	w.Flush()
+
+	// comments, to keep proxies from closing the stream
+	ticker := time.NewTicker(server.idleTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case down, ok := <-downCh:
+			if !ok {
+				// server wants us gone
+				return
+			}
+			msg, err := encodeDown(down)
+			if err != nil {
+				log.Info().Err(err).Msg("encode error")
+				return
+			}
+			err = writeSSE(w, res.Token, msg)
+			if err != nil {
+				log.Info().Err(err).Msg("send error")
+				return
+			}
+		case <-ticker.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			if err != nil {
+				log.Info().Err(err).Msg("send error")
+				return
+			}
+		case <-c.Request.Context().Done():
+			log.Info().Msg("events client gone")
+			return
+		}
+		w.Flush()
+	}
+}
+
+// writeSSE writes a message as an event, named by its head, with the data as
+// JSON. Numbered messages get ids that can be used to resume.
+func writeSSE(w io.Writer, token string, msg comms.Message) error {
+	var data []byte
+	switch msg.Content {
+	case comms.ContentJSON:
+		data = msg.Data
+	case comms.ContentText:
+		data, _ = json.Marshal(string(msg.Data))
+	default:
+		data, _ = json.Marshal(msg.Data)
+	}
+
+	buf := &bytes.Buffer{}
+	if msg.Seq != 0 {
+		fmt.Fprintf(buf, "id: %s.%d\n", token, msg.Seq)
+	}
+	fmt.Fprintf(buf, "event: %s\n", msg.Head)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fmt.Fprintf(buf, "data: %s\n", line)
+	}
+	buf.WriteString("\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
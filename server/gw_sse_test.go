@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestWriteSSE(t *testing.T) {
+	buf := &bytes.Buffer{}
+	msg, _ := comms.Encode("update", map[string]int{"turnNumber": 3})
+	msg.Seq = 7
+	if err := writeSSE(buf, "tok", msg); err != nil {
+		t.Fatal(err)
+	}
+	exp := "id: tok.7\nevent: update\ndata: {\"turnNumber\":3}\n\n"
+	if buf.String() != exp {
+		t.Errorf("bad event: %q", buf.String())
+	}
+
+	// not numbered, so can't be resumed from
+	buf.Reset()
+	msg, _ = comms.Encode("connected", "hi")
+	writeSSE(buf, "tok", msg)
+	exp = "event: connected\ndata: \"hi\"\n\n"
+	if buf.String() != exp {
+		t.Errorf("bad event: %q", buf.String())
+	}
+}
+
+func TestServeSSE_longerThanWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &server{coreCh: make(chan interface{}, 10), idleTimeout: time.Minute}
+
+	// a core that has an update for the client, but only after a while
+	go func() {
+		for in := range s.coreCh {
+			if msg, ok := in.(watchMsg); ok {
+				msg.Rep <- connectResult{Token: "tok"}
+				go func() {
+					time.Sleep(500 * time.Millisecond)
+					msg.Client.downCh <- toSend{"update", game.GameUpdate{Playing: "phil"}}
+				}()
+			}
+		}
+	}()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newWebServer(s, log.Logger)
+	srv.WriteTimeout = 100 * time.Millisecond
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	url := fmt.Sprintf("http://%s/api/games/g1/events?c=%s", ln.Addr(), encodeConnectString("g1", "phil"))
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var events []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() && len(events) < 2 {
+		if line := scanner.Text(); strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		}
+	}
+	if len(events) != 2 || events[1] != "update" {
+		t.Errorf("expected update after the write timeout, got %v %v", events, scanner.Err())
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net"
+	"time"
 
 	"github.com/undeconstructed/gogogo/comms"
 
@@ -20,11 +22,17 @@ func runTcpGateway(ctx context.Context, server *server, addr string) error {
 		return err
 	}
 
-	log.Info().Msgf("comms listening on tcp:%v", ln.Addr())
+	if server.tlsConfig != nil {
+		ln = tls.NewListener(ln, server.tlsConfig)
+		log.Info().Msgf("comms listening on tls:%v", ln.Addr())
+	} else {
+		log.Info().Msgf("comms listening on tcp:%v", ln.Addr())
+	}
 
 	m := &tcpManager{
-		server: server,
-		log:    log,
+		server:      server,
+		idleTimeout: server.idleTimeout,
+		log:         log,
 	}
 	go func() {
 		err := m.Serve(ln)
@@ -39,8 +47,9 @@ func runTcpGateway(ctx context.Context, server *server, addr string) error {
 }
 
 type tcpManager struct {
-	server *server
-	log    zerolog.Logger
+	server      *server
+	idleTimeout time.Duration
+	log         zerolog.Logger
 }
 
 func (m *tcpManager) Serve(ln net.Listener) error {
@@ -60,13 +69,18 @@ func (m *tcpManager) manageTcpConnection(conn net.Conn) {
 	log.Info().Msgf("connecting")
 
 	downCh := make(chan interface{}, 100)
+	// pongs are sent by the writer, so that writes are not interleaved
+	pongCh := make(chan struct{}, 1)
 
 	upStream := comms.NewDecoder(conn)
 	dnStream := comms.NewEncoder(conn)
 
 	go func() {
+		defer conn.Close()
+
 		var gameId, playerId string
 
+		conn.SetReadDeadline(time.Now().Add(m.idleTimeout))
 		msg1, err := upStream.Decode()
 		if err != nil {
 			log.Info().Err(err).Msg("first message error")
@@ -86,10 +100,17 @@ func (m *tcpManager) manageTcpConnection(conn net.Conn) {
 				return
 			}
 
-			err = m.server.Connect(gameId, playerId, clientBundle{downCh})
+			req := comms.ConnectRequest{}
+			err = comms.Decode(msg1, &req)
 			if err != nil {
-				log.Info().Err(err).Msg("connect error")
-				dnStream.Encode("connected", comms.ConnectResponse{Err: comms.WrapError(err)})
+				log.Info().Err(err).Msg("bad connect request")
+				return
+			}
+
+			res := m.server.Connect(gameId, playerId, req, clientBundle{downCh})
+			if res.Err != nil {
+				log.Info().Err(res.Err).Msg("connect error")
+				dnStream.Encode("connected", comms.ConnectResponse{Err: comms.WrapError(res.Err)})
 				return
 			}
 
@@ -97,37 +118,75 @@ func (m *tcpManager) manageTcpConnection(conn net.Conn) {
 			dnStream.Encode("connected", comms.ConnectResponse{
 				GameID:   gameId,
 				PlayerID: playerId,
+				Resume:   res.Token,
+				Resumed:  res.Resumed,
 			})
 		}
 
 		go func() {
-			// read downCh, write to conn
-			for down := range downCh {
-				msg, err := encodeDown(down)
-				if err != nil {
-					log.Info().Err(err).Msg("encode error")
-					break
+			// ping often enough that a live client is never idle
+			ticker := time.NewTicker(m.idleTimeout / 3)
+			defer ticker.Stop()
+
+		loop:
+			for {
+				// read downCh, write to conn
+				var msg comms.Message
+				var err error
+				select {
+				case down, ok := <-downCh:
+					if !ok {
+						break loop
+					}
+					msg, err = encodeDown(down)
+					if err != nil {
+						log.Info().Err(err).Msg("encode error")
+						break loop
+					}
+				case <-pongCh:
+					msg, _ = comms.Encode(comms.TypePong, nil)
+				case <-ticker.C:
+					msg, _ = comms.Encode(comms.TypePing, nil)
 				}
 				err = dnStream.Send(msg)
 				if err != nil {
 					log.Info().Err(err).Msg("send error")
-					break
+					break loop
 				}
 			}
+			// server wants us gone
+			conn.Close()
 		}()
 
 		for {
 			// read conn, despatch into server
+			conn.SetReadDeadline(time.Now().Add(m.idleTimeout))
 			msg, err := upStream.Decode()
 			if err != nil {
-				if err != io.EOF {
+				if ne, ok := err.(net.Error); ok && ne.Timeout() {
+					log.Info().Msg("client idle, dropping")
+				} else if err != io.EOF {
 					log.Info().Err(err).Msg("decode error")
 				}
 				break
 			}
-			log.Info().Msgf("received: %s %s", msg.Head, string(msg.Data))
 
 			f := msg.Head.Fields()
+			switch f[0] {
+			case comms.TypePing:
+				select {
+				case pongCh <- struct{}{}:
+				default:
+					// a pong is already waiting
+				}
+				continue
+			case comms.TypePong:
+				// nothing to do, just proves the client is there
+				continue
+			}
+
+			log.Info().Msgf("received: %s %s", msg.Head, string(msg.Data))
+
 			switch f[0] {
 			case "text":
 				var text string
@@ -148,6 +207,6 @@ func (m *tcpManager) manageTcpConnection(conn net.Conn) {
 			}
 		}
 
-		m.server.coreCh <- disconnectMsg{gameId, playerId}
+		m.server.coreCh <- disconnectMsg{gameId, playerId, clientBundle{downCh}}
 	}()
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/undeconstructed/gogogo/comms"
+)
+
+func TestTcpHeartbeat(t *testing.T) {
+	s := &server{coreCh: make(chan interface{}, 10)}
+	go func() {
+		for in := range s.coreCh {
+			if msg, ok := in.(connectMsg); ok {
+				msg.Rep <- connectResult{Token: "t"}
+			}
+		}
+	}()
+	// not closed, as disconnects come in after the test
+
+	idle := 300 * time.Millisecond
+	m := &tcpManager{server: s, idleTimeout: idle, log: log.Logger}
+
+	// connect starts a client, and gives a channel of the types of what it
+	// gets, which is closed when the connection is
+	connect := func() (net.Conn, <-chan string) {
+		cconn, sconn := net.Pipe()
+		m.manageTcpConnection(sconn)
+
+		enc := comms.NewEncoder(cconn)
+		enc.Encode("connect:"+encodeConnectString("g1", "phil"), comms.ConnectRequest{})
+
+		ch := make(chan string, 100)
+		go func() {
+			defer close(ch)
+			dec := comms.NewDecoder(cconn)
+			for {
+				msg, err := dec.Decode()
+				if err != nil {
+					return
+				}
+				ch <- msg.Head.Fields()[0]
+			}
+		}()
+		if got := <-ch; got != "connected" {
+			t.Fatalf("expected connected, got %s", got)
+		}
+		return cconn, ch
+	}
+
+	// a silent client is pinged, and then dropped
+	_, ch := connect()
+	start := time.Now()
+	pings := 0
+	for got := range ch {
+		if got == comms.TypePing {
+			pings++
+		}
+	}
+	if pings == 0 {
+		t.Errorf("expected pings before being dropped")
+	}
+	if d := time.Since(start); d < idle*2/3 || d > 3*idle {
+		t.Errorf("expected drop after about %v, took %v", idle, d)
+	}
+
+	// a client that pings is kept, and gets pongs
+	cconn, ch := connect()
+	enc := comms.NewEncoder(cconn)
+	pongs := 0
+	timeout := time.After(3 * idle)
+	tick := time.NewTicker(idle / 3)
+	defer tick.Stop()
+loop:
+	for {
+		select {
+		case got, ok := <-ch:
+			if !ok {
+				t.Fatalf("expected live client kept")
+			}
+			if got == comms.TypePong {
+				pongs++
+			}
+		case <-tick.C:
+			enc.Encode(comms.TypePing, nil)
+		case <-timeout:
+			break loop
+		}
+	}
+	if pongs == 0 {
+		t.Errorf("expected pongs")
+	}
+	cconn.Close()
+}
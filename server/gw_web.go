@@ -2,16 +2,16 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
-	"path"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
@@ -19,11 +19,6 @@ import (
 	"nhooyr.io/websocket"
 )
 
-type WsJSONMessage struct {
-	Head string          `json:"head"`
-	Data json.RawMessage `json:"data"`
-}
-
 func runWebGateway(ctx context.Context, server *server, addr string) error {
 	log := log.With().Str("gw", "web").Logger()
 
@@ -32,8 +27,49 @@ func runWebGateway(ctx context.Context, server *server, addr string) error {
 		return err
 	}
 
-	log.Info().Msgf("web listening on http://%v", ln.Addr())
+	if server.tlsConfig != nil {
+		ln = tls.NewListener(ln, server.tlsConfig)
+		log.Info().Msgf("web listening on https://%v", ln.Addr())
+	} else {
+		log.Info().Msgf("web listening on http://%v", ln.Addr())
+	}
+
+	s := newWebServer(server, log)
+	go func() {
+		err := s.Serve(ln)
+		log.Info().Err(err).Msg("server return")
+	}()
+	go func() {
+		<-ctx.Done()
+		s.Shutdown(context.TODO())
+	}()
+
+	return nil
+}
 
+// newWebServer makes the HTTP server, which has timeouts for ordinary
+// requests. Streams have to clear them for themselves.
+func newWebServer(server *server, log zerolog.Logger) *http.Server {
+	return &http.Server{
+		Handler:      withRawWriter(newWebRouter(server, log)),
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 10,
+	}
+}
+
+// rawWriterKey is for the request context value that is the writer that gin
+// wraps, because http.NewResponseController can't see through gin's.
+type rawWriterKey struct{}
+
+func withRawWriter(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), rawWriterKey{}, w)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// newWebRouter routes the REST API, the websocket and the web clients.
+func newWebRouter(server *server, log zerolog.Logger) *gin.Engine {
 	rh := restHandler{
 		server: server,
 		log:    log,
@@ -44,58 +80,69 @@ func runWebGateway(ctx context.Context, server *server, addr string) error {
 		log:    log,
 	}
 
+	ah := adminHandler{
+		server: server,
+		log:    log,
+	}
+
+	uh := accountHandler{
+		server: server,
+		log:    log,
+	}
+
 	r := gin.Default()
 
+	auth := requireAuth(server)
+
 	a := r.Group("/api")
+	a.GET("/types", rh.getTypes)
 	a.GET("/games", rh.getGames)
-	a.POST("/games", rh.makeGame)
+	a.POST("/games", auth, rh.makeGame)
 	a.GET("/games/:id", rh.getGame)
-	a.DELETE("/games/:id", rh.deleteGame)
+	a.DELETE("/games/:id", auth, rh.deleteGame)
+	a.POST("/games/:id/fork", auth, rh.forkGame)
+	a.GET("/games/:id/export", auth, rh.exportGame)
+	a.GET("/games/:id/events", ch.serveSSE)
+	a.POST("/games/:id/start", rh.playStart)
+	a.POST("/games/:id/play", rh.playPlay)
+	a.GET("/games/:id/state", rh.playState)
+	a.GET("/games/:id/webhooks", auth, rh.getHooks)
+	a.POST("/games/:id/webhooks", auth, rh.addHook)
+	a.DELETE("/games/:id/webhooks/:hook", auth, rh.deleteHook)
+	a.GET("/leaderboards/:type", rh.getLeaderboard)
+	a.GET("/players/:name/history", rh.getHistory)
+	a.GET("/archive", rh.getArchive)
+	a.GET("/archive/:id", rh.getArchived)
+	a.POST("/imports", auth, rh.importGame)
+	a.GET("/webhooks", auth, rh.getHooks)
+	a.POST("/webhooks", auth, rh.addHook)
+	a.DELETE("/webhooks/:hook", auth, rh.deleteHook)
 	r.GET("/ws", ch.serveWS)
 
-	r.GET("/play/:type/*any", func(c *gin.Context) {
-		urlPath := c.Request.URL.EscapedPath()
-		gameType := c.Param("type")
-		if strings.HasSuffix(urlPath, "/") {
-			// index page
-			key := c.Query("c")
-			if key == "" {
-				// home page
-				c.File(path.Join(".", "run", gameType, "web", "home.html"))
-			} else {
-				// game page
-				c.File(path.Join(".", "run", gameType, "web", "index.html"))
-			}
-		} else if strings.HasSuffix(urlPath, "/data.json") {
-			// data file ..
-			c.File(path.Join(".", "run", gameType, "data.json"))
-		} else {
-			// any other resource
-			dir := http.Dir(path.Join(".", "run", gameType, "web"))
-			rest := urlPath[len(gameType)+6:]
-			// XXX - shouldn't blindly serve everything
-			c.FileFromFS(rest, dir)
-		}
-	})
+	accounts := requireAccounts(server)
+	account := requireAccount(server)
+	a.POST("/accounts", accounts, uh.createAccount)
+	a.POST("/login", accounts, uh.login)
+	a.POST("/logout", accounts, account, uh.logout)
+	me := a.Group("/me", accounts, account)
+	me.GET("", uh.getMe)
+	me.GET("/games", uh.getMyGames)
+	a.PUT("/games/:id/seats/:seat", accounts, auth, uh.linkSeat)
+
+	aa := a.Group("/admin", auth, requireAdmin())
+	aa.GET("/instances", ah.getInstances)
+	aa.POST("/instances/:id/restart", ah.restartInstance)
+	aa.GET("/instances/:id/save", ah.getSave)
+	aa.POST("/instances/:id/end", ah.endInstance)
+	aa.DELETE("/instances/:id/clients/:name", ah.kickClient)
+	aa.POST("/types/:type/upgrade", ah.upgradeType)
+
+	r.GET("/play/:type/*any", rh.getPlayFile)
 
 	commonFS := http.Dir("web")
 	r.StaticFS("/common/", commonFS)
 
-	s := &http.Server{
-		Handler:      r,
-		ReadTimeout:  time.Second * 10,
-		WriteTimeout: time.Second * 10,
-	}
-	go func() {
-		err := s.Serve(ln)
-		log.Info().Err(err).Msg("server return")
-	}()
-	go func() {
-		<-ctx.Done()
-		s.Shutdown(context.TODO())
-	}()
-
-	return nil
+	return r
 }
 
 type restHandler struct {
@@ -103,6 +150,11 @@ type restHandler struct {
 	log    zerolog.Logger
 }
 
+func (rh *restHandler) getTypes(c *gin.Context) {
+	list := rh.server.ListTypes()
+	c.JSON(http.StatusOK, list)
+}
+
 func (rh *restHandler) getGames(c *gin.Context) {
 	list := rh.server.ListGames()
 	c.JSON(http.StatusOK, list)
@@ -118,10 +170,6 @@ func (rh *restHandler) makeGame(c *gin.Context) {
 		c.String(http.StatusBadRequest, "missing game type")
 		return
 	}
-	if len(i.Players) < 1 || len(i.Players) > 6 {
-		c.String(http.StatusBadRequest, "must have 1-6 players")
-		return
-	}
 	for _, pl := range i.Players {
 		if pl.Name == "" {
 			c.String(http.StatusBadRequest, "invalid player")
@@ -129,7 +177,23 @@ func (rh *restHandler) makeGame(c *gin.Context) {
 		}
 	}
 
-	res := rh.server.CreateGame(i)
+	var desc *game.Description
+	for _, t := range rh.server.ListTypes() {
+		if t.Name == i.Type {
+			desc = &t
+			break
+		}
+	}
+	if desc == nil {
+		c.String(http.StatusBadRequest, "unknown game type")
+		return
+	}
+	if err := checkMakeGame(*desc, i); err != nil {
+		c.String(http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	res := rh.server.CreateGame(i, getAuthUser(c).Name)
 	if res.Err != nil {
 		c.JSON(http.StatusInternalServerError, res)
 		return
@@ -154,6 +218,72 @@ func (rh *restHandler) getGame(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+// saveError sends errors from forking, exporting and importing.
+func saveError(c *gin.Context, err error) {
+	switch {
+	case err == errNotAllowed:
+		c.String(http.StatusForbidden, "error: %v", err)
+	case err == errGameNotFound:
+		c.String(http.StatusNotFound, "error: %v", err)
+	case game.Code(err) == game.StatusBadRequest:
+		c.String(http.StatusBadRequest, "error: %v", err)
+	case game.Code(err) == game.StatusConflict:
+		c.String(http.StatusConflict, "error: %v", err)
+	default:
+		c.String(http.StatusInternalServerError, "error: %v", err)
+	}
+}
+
+func (rh *restHandler) forkGame(c *gin.Context) {
+	i := ForkGameInput{}
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&i); err != nil {
+			return
+		}
+	}
+
+	res, err := rh.server.ForkGame(c.Param("id"), getAuthUser(c), i)
+	if err != nil {
+		saveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (rh *restHandler) exportGame(c *gin.Context) {
+	id := c.Param("id")
+	b, err := rh.server.ExportGame(id, getAuthUser(c))
+	if err != nil {
+		saveError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", b.Type+"-"+id+".json"))
+	c.JSON(http.StatusOK, b)
+}
+
+// maxBundleSize is the biggest bundle that can be imported, which is far more
+// than any real game's save and news.
+const maxBundleSize = 16 << 20
+
+func (rh *restHandler) importGame(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBundleSize)
+
+	b := &GameBundle{}
+	if err := c.BindJSON(b); err != nil {
+		return
+	}
+
+	res, err := rh.server.ImportGame(b, getAuthUser(c))
+	if err != nil {
+		saveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
 func (rh *restHandler) deleteGame(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -161,8 +291,11 @@ func (rh *restHandler) deleteGame(c *gin.Context) {
 		return
 	}
 
-	err := rh.server.DeleteGame(id)
-	if err != nil {
+	err := rh.server.DeleteGame(id, getAuthUser(c))
+	if err == errNotAllowed {
+		c.String(http.StatusForbidden, "error: %v", err)
+		return
+	} else if err != nil {
 		c.String(http.StatusInternalServerError, "error: %v", err)
 		return
 	}
@@ -188,6 +321,17 @@ func (ch *commsHandler) serveWS(c *gin.Context) {
 		return
 	}
 
+	// resume details, if any, are in the query, as there is no first message
+	req := comms.ConnectRequest{Resume: c.Query("resume"), Patches: c.Query("patches") == "true"}
+	if seq := c.Query("seq"); seq != "" {
+		lastSeq, err := strconv.ParseUint(seq, 10, 32)
+		if err != nil {
+			c.String(http.StatusBadRequest, "bad seq")
+			return
+		}
+		req.LastSeq = uint32(lastSeq)
+	}
+
 	server := ch.server
 
 	// ws stuff
@@ -214,11 +358,11 @@ func (ch *commsHandler) serveWS(c *gin.Context) {
 
 	downCh := make(chan interface{}, 100)
 
-	err = server.Connect(gameId, playerId, clientBundle{downCh})
-	if err != nil {
+	res := server.Connect(gameId, playerId, req, clientBundle{downCh})
+	if res.Err != nil {
 		// TODO - if game not found, maybe StatusGoingAway?
-		log.Info().Err(err).Msgf("connection error, refusing")
-		msg, _ := comms.Encode("connected", comms.ConnectResponse{Err: comms.WrapError(err)})
+		log.Info().Err(res.Err).Msgf("connection error, refusing")
+		msg, _ := comms.Encode("connected", comms.ConnectResponse{Err: comms.WrapError(res.Err)})
 		sendDownWs(ctx, socket, msg)
 		socket.Close(websocket.StatusNormalClosure, "cannot connect")
 		return
@@ -228,6 +372,8 @@ func (ch *commsHandler) serveWS(c *gin.Context) {
 	msg, _ := comms.Encode("connected", comms.ConnectResponse{
 		GameID:   gameId,
 		PlayerID: playerId,
+		Resume:   res.Token,
+		Resumed:  res.Resumed,
 	})
 	sendDownWs(ctx, socket, msg)
 
@@ -249,21 +395,53 @@ func (ch *commsHandler) serveWS(c *gin.Context) {
 		socket.Close(websocket.StatusGoingAway, "server closure")
 	}()
 
+	go func() {
+		// websocket pings, which browsers answer by themselves
+		ticker := time.NewTicker(server.idleTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				pctx, pcancel := context.WithTimeout(ctx, server.idleTimeout)
+				err := socket.Ping(pctx)
+				pcancel()
+				if err != nil {
+					log.Info().Err(err).Msg("client idle, dropping")
+					socket.Close(websocket.StatusGoingAway, "idle")
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	for {
 		// read conn, despatch into server
 		msg, err = readMessageWs(ctx, socket)
 		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
-			server.coreCh <- disconnectMsg{gameId, playerId}
+			server.coreCh <- disconnectMsg{gameId, playerId, clientBundle{downCh}}
 			return
 		}
 		if err != nil {
 			log.Info().Err(err).Msgf("client read error")
-			server.coreCh <- disconnectMsg{gameId, playerId}
+			server.coreCh <- disconnectMsg{gameId, playerId, clientBundle{downCh}}
 			return
 		}
-		log.Info().Msgf("received [%s %s]", msg.Head, string(msg.Data))
 
 		f := msg.Head.Fields()
+		switch f[0] {
+		case comms.TypePing:
+			// the websocket allows concurrent writers
+			pong, _ := comms.Encode(comms.TypePong, nil)
+			sendDownWs(ctx, socket, pong)
+			continue
+		case comms.TypePong:
+			continue
+		}
+
+		log.Info().Msgf("received [%s %s]", msg.Head, string(msg.Data))
+
 		switch f[0] {
 		case "text":
 			var text string
@@ -293,14 +471,11 @@ func sendDownWs(ctx context.Context, ws *websocket.Conn, msg comms.Message) erro
 	}
 	defer w.Close()
 
-	jmsg := WsJSONMessage{
-		Head: string(msg.Head),
-		// XXX - not everything always is json!
-		Data: json.RawMessage(msg.Data),
+	tmsg, err := comms.MarshalJSONMessage(msg)
+	if err != nil {
+		return err
 	}
 
-	tmsg, _ := json.Marshal(jmsg)
-
 	_, err = w.Write(tmsg)
 	if err != nil {
 		return err
@@ -321,13 +496,7 @@ func readMessageWs(ctx context.Context, c *websocket.Conn) (comms.Message, error
 		if err != nil {
 			return comms.Message{}, err
 		}
-		msg := WsJSONMessage{}
-		err = json.Unmarshal(bytes, &msg)
-		if err != nil {
-			return comms.Message{}, err
-		}
-
-		return comms.Message{Head: comms.Head(msg.Head), Data: msg.Data}, nil
+		return comms.UnmarshalJSONMessage(bytes)
 	} else {
 		return comms.Message{}, fmt.Errorf("client sent a %v", typ)
 	}
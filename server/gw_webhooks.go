@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Webhooks are under /api/webhooks for the whole server, and under
+// /api/games/:id/webhooks for one game, so the game id param may be empty.
+
+func hookError(c *gin.Context, err error) {
+	switch err {
+	case errNotAllowed:
+		c.String(http.StatusForbidden, "error: %v", err)
+	case errGameNotFound, errHookNotFound:
+		c.String(http.StatusNotFound, "error: %v", err)
+	default:
+		c.String(http.StatusInternalServerError, "error: %v", err)
+	}
+}
+
+func (rh *restHandler) getHooks(c *gin.Context) {
+	list, err := rh.server.ListHooks(c.Param("id"), getAuthUser(c))
+	if err != nil {
+		hookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (rh *restHandler) addHook(c *gin.Context) {
+	i := WebhookInput{}
+	if err := c.BindJSON(&i); err != nil {
+		return
+	}
+	if err := i.check(c.Request.Context(), rh.server.hookPolicy); err != nil {
+		c.String(http.StatusBadRequest, "error: %v", err)
+		return
+	}
+
+	hook, err := rh.server.AddHook(c.Param("id"), getAuthUser(c), i)
+	if err != nil {
+		hookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, hook)
+}
+
+func (rh *restHandler) deleteHook(c *gin.Context) {
+	err := rh.server.RemoveHook(c.Param("id"), getAuthUser(c), c.Param("hook"))
+	if err != nil {
+		hookError(c, err)
+		return
+	}
+	c.String(http.StatusOK, "ok: %s", c.Param("hook"))
+}
@@ -5,170 +5,203 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/undeconstructed/gogogo/comms"
 	"github.com/undeconstructed/gogogo/game"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// errNotRunning is for a game that has no plugin, e.g. after being ended.
+var errNotRunning = errors.New("game not running")
+
 // instance is combined game instance and plugin instance
 type instance struct {
 	// game type, e.g. go.
 	gameType string
 	// unique id
 	id string
-	// gRPC client connecting to plugin
-	cli game.InstanceClient
+	// server's own data about the game
+	meta gameMeta
+	// plugin processes for the type
+	pool *pluginPool
+	// plugin process that has the game
+	host *pluginHost
 	// cached last seen state
 	state *game.RGameState
 	// player clients
 	clients map[string]*clientBundle
+	// player sessions, which last between connections
+	sessions map[string]*session
+	// read-only streams of players' messages, which don't take the seat
+	watchers map[string][]*clientBundle
+	// the state when last checked for changes
+	seen seenState
 
 	// internal stuff
-	stopCh chan struct{}
-	log    zerolog.Logger
+	log zerolog.Logger
 }
 
 func newInstance(gameType string, id string) *instance {
-	stopCh := make(chan struct{})
 	log := log.With().Str("instance", id).Logger()
 
 	return &instance{
 		gameType: gameType,
 		id:       id,
 		clients:  map[string]*clientBundle{},
-		stopCh:   stopCh,
+		sessions: map[string]*session{},
+		watchers: map[string][]*clientBundle{},
 		log:      log,
 	}
 }
 
-func (i *instance) startProcess(ctx context.Context) (game.InstanceClient, error) {
+// startHost gets the game a place in a plugin process.
+func (i *instance) startHost(ctx context.Context) (*pluginHost, error) {
 	i.log.Info().Msg("instance starting")
 
-	// run dir
-	dir := "./" + path.Join("run", i.gameType)
-	// relative binary path
-	bin := "./bin"
-	// relative bind file
-	bind := path.Join("bind", i.id+".pipe")
-
-	log.Info().Msgf("will bind to: %s", bind)
-
-	pro := newProcess(dir, bin, bind)
-
-	ctx1, cancel := context.WithCancel(ctx)
-
-	conn, err := pro.Start(ctx1)
+	h, err := i.pool.acquire(ctx, i.id)
 	if err != nil {
-		cancel()
 		return nil, err
 	}
 
-	go func() {
-		select {
-		case <-i.stopCh:
-			// internal stop via destroy
-		case <-ctx.Done():
-			// external stop via context
-		}
-
-		cancel()
-	}()
+	i.host = h
 
-	cli := game.NewInstanceClient(conn)
-
-	return cli, nil
+	return h, nil
 }
 
 func (i *instance) StartInit(ctx context.Context, in MakeGameInput) error {
-	cli, err := i.startProcess(ctx)
+	h, err := i.startHost(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = i.doInit(ctx, cli, in)
+	err = i.doInit(ctx, h, in)
 	if err != nil {
 		return err
 	}
 	i.log.Info().Msg("instance inited")
 
-	i.cli = cli
-
 	return nil
 }
 
-func (i *instance) doInit(ctx context.Context, cli game.InstanceClient, in MakeGameInput) error {
-	res, err := cli.Init(ctx, &game.RInitRequest{
-		Id:      i.id,
-		Options: []byte(in.Options),
-	})
-	if err != nil {
-		err := status.Convert(err)
-		return fmt.Errorf("Can't create game: %s", err.Message())
-	}
-
-	i.state = res.State
-
-	for _, p := range in.Players {
-		res, err := cli.AddPlayer(ctx, &game.RAddPlayerRequest{Name: p.Name, Options: p.Options})
+func (i *instance) doInit(ctx context.Context, h *pluginHost, in MakeGameInput) error {
+	return h.call(func(cli game.InstanceClient) error {
+		res, err := cli.Init(ctx, &game.RInitRequest{
+			Id:      i.id,
+			Options: orEmptyObject(in.Options),
+		})
 		if err != nil {
 			err := status.Convert(err)
-			return fmt.Errorf("Can't add player: %s", err.Message())
+			return fmt.Errorf("Can't create game: %s", err.Message())
 		}
+		h.ready(i.id)
+
 		i.state = res.State
-	}
 
-	return nil
+		for _, p := range in.Players {
+			res, err := cli.AddPlayer(ctx, &game.RAddPlayerRequest{Game: i.id, Name: p.Name, Options: orEmptyObject(p.Options)})
+			if err != nil {
+				err := status.Convert(err)
+				return fmt.Errorf("Can't add player: %s", err.Message())
+			}
+			i.state = res.State
+		}
+
+		return nil
+	})
 }
 
 func (i *instance) StartLoad(ctx context.Context) error {
-	cli, err := i.startProcess(ctx)
+	h, err := i.startHost(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = i.doLoad(ctx, cli)
+	err = i.doLoad(ctx, h)
 	if err != nil {
 		return err
 	}
 	i.log.Info().Msg("instance loaded")
 
-	i.cli = cli
-
 	return nil
 }
 
-func (i *instance) doLoad(ctx context.Context, cli game.InstanceClient) error {
-	res, err := cli.Load(ctx, &game.RLoadRequest{Id: i.id})
+// restartGame stops the plugin process that has a game, and starts a new one
+// that loads the game from its save file, along with any other games that
+// were in the process. With no process, the game gets a new place in the pool.
+// If it fails, the game is left in no process. This is not for the core, so it
+// leaves the instance alone, and gives back what to put into it.
+func restartGame(ctx context.Context, pool *pluginPool, h *pluginHost, id string) (*pluginHost, *game.RGameState, error) {
+	var state *game.RGameState
+	var err error
+	if h == nil {
+		// not running, e.g. it was ended, or failed to start
+		h, err = pool.acquire(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		state, err = loadGame(ctx, h, id)
+	} else {
+		state, err = pool.restart(h, id)
+	}
+	if err != nil {
+		pool.release(h, id)
+		return nil, nil, err
+	}
+
+	return h, state, nil
+}
+
+func (i *instance) doLoad(ctx context.Context, h *pluginHost) error {
+	state, err := loadGame(ctx, h, i.id)
 	if err != nil {
 		return err
 	}
 
-	i.state = res.State
+	i.state = state
 
 	return nil
 }
 
-func (i *instance) Start() error {
-	if i.cli == nil {
-		panic("no client")
+// loadGame loads a game into a plugin process.
+func loadGame(ctx context.Context, h *pluginHost, id string) (*game.RGameState, error) {
+	var res *game.RLoadResponse
+	err := h.call(func(cli game.InstanceClient) (err error) {
+		res, err = cli.Load(ctx, &game.RLoadRequest{Id: id})
+		if err == nil {
+			h.ready(id)
+		}
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.State, nil
+}
+
+// Start starts the game. Like Play, it's called off the core, with the host
+// that the game had when the request came in, as the core can take the game
+// out of it meanwhile.
+func (i *instance) Start(h *pluginHost) error {
+	if h == nil {
+		return errNotRunning
 	}
 
-	res, err := i.cli.Start(context.TODO(), &game.RStartRequest{})
+	var res *game.RStartResponse
+	err := h.call(func(cli game.InstanceClient) (err error) {
+		res, err = cli.Start(context.TODO(), &game.RStartRequest{Game: i.id})
+		return
+	})
 	if err != nil {
-		se, _ := status.FromError(err)
-		switch se.Code() {
-		case codes.FailedPrecondition:
-			return errors.New(err.Error())
-		case codes.InvalidArgument:
-			return errors.New(err.Error())
-		case codes.Unavailable:
+		if status.Code(err) == codes.Unavailable {
 			log.Warn().Err(err).Msg("rpc unavailable")
 		}
-		return err
+		return game.ErrorFromGRPC(err)
 	}
 
 	i.state = res.State
@@ -176,28 +209,27 @@ func (i *instance) Start() error {
 	return nil
 }
 
-func (i *instance) Play(player string, c game.Command) ([]game.Change, json.RawMessage, error) {
-	if i.cli == nil {
-		panic("no client")
+func (i *instance) Play(h *pluginHost, player string, c game.Command) ([]game.Change, json.RawMessage, error) {
+	if h == nil {
+		return nil, nil, errNotRunning
 	}
 
-	res, err := i.cli.Play(context.TODO(), &game.RPlayRequest{
-		Player:  player,
-		Command: string(c.Command),
-		Options: c.Options,
+	var res *game.RPlayResponse
+	err := h.call(func(cli game.InstanceClient) (err error) {
+		res, err = cli.Play(context.TODO(), &game.RPlayRequest{
+			Game:    i.id,
+			Player:  player,
+			Command: string(c.Command),
+			Options: c.Options,
+		})
+		return
 	})
 
 	if err != nil {
-		se, _ := status.FromError(err)
-		switch se.Code() {
-		case codes.FailedPrecondition:
-			return nil, nil, errors.New(se.Message())
-		case codes.InvalidArgument:
-			return nil, nil, errors.New(se.Message())
-		case codes.Unavailable:
+		if status.Code(err) == codes.Unavailable {
 			log.Warn().Err(err).Msg("rpc unavailable")
 		}
-		return nil, nil, err
+		return nil, nil, game.ErrorFromGRPC(err)
 	}
 
 	i.state = res.State
@@ -209,8 +241,135 @@ func (i *instance) GetGameState() *game.RGameState {
 	return i.state
 }
 
+// send sends something down to a player, through their session, so that it
+// can be replayed if they are not connected now.
+func (i *instance) send(player string, down interface{}) error {
+	msg, err := encodeDown(down)
+	if err != nil {
+		return err
+	}
+	return i.sendMessage(player, msg, msg)
+}
+
+// sendMessage numbers a message in a player's session, and sends it to the
+// player's client, and the watched version of it to anything watching.
+func (i *instance) sendMessage(player string, msg, watched comms.Message) error {
+	sess, ok := i.sessions[player]
+	if !ok {
+		return errNotConnected
+	}
+
+	msg = sess.number(msg)
+	watched.Seq = msg.Seq
+	for _, w := range i.watchers[player] {
+		err := w.trySend(watched)
+		if err != nil {
+			i.log.Info().Err(err).Msgf("watcher lagging: %s", player)
+		}
+	}
+
+	client, ok := i.clients[player]
+	if !ok {
+		return errNotConnected
+	}
+	return client.trySend(msg)
+}
+
+// sendUpdate sends a player an update, as a patch if the session takes them.
+// Watchers always get the whole update, as they may not have seen the base.
+func (i *instance) sendUpdate(player string, update game.GameUpdate) error {
+	sess, ok := i.sessions[player]
+	if !ok {
+		return errNotConnected
+	}
+
+	msg, doc, err := sess.encodeUpdate(update)
+	if err != nil {
+		return err
+	}
+	watched := msg
+	if msg.Type() == "patch" && len(i.watchers[player]) > 0 {
+		watched, err = comms.Encode("update", update)
+		if err != nil {
+			return err
+		}
+	}
+	err = i.sendMessage(player, msg, watched)
+	if err != nil {
+		// the client might never see this, and can't take a patch against it,
+		// nor against the last one, if this is replayed to it later, so the
+		// next update goes whole
+		sess.base, sess.baseSeq = nil, 0
+		return err
+	}
+	sess.base, sess.baseSeq = doc, sess.seq
+	return nil
+}
+
+// dropClients disconnects every client and watcher, e.g. when the game ends.
+func (i *instance) dropClients() {
+	for name, client := range i.clients {
+		close(client.downCh)
+		delete(i.clients, name)
+	}
+	for name, list := range i.watchers {
+		for _, w := range list {
+			close(w.downCh)
+		}
+		delete(i.watchers, name)
+	}
+}
+
+// Health is a simple description of whether the plugin can be reached.
+func (i *instance) Health() string {
+	if i.host == nil {
+		return "none"
+	}
+	return i.host.Health()
+}
+
+// Info is the admin view of the instance.
+func (i *instance) Info() InstanceInfo {
+	info := InstanceInfo{
+		ID:      i.id,
+		Type:    i.gameType,
+		Owner:   i.meta.Owner,
+		Health:  i.Health(),
+		Clients: []string{},
+	}
+	if i.state != nil {
+		info.Status = game.GameStatus(i.state.Status)
+	}
+	if i.host != nil {
+		info.PID = i.host.Pid()
+		info.Bind = i.host.Path()
+	}
+	for name := range i.clients {
+		info.Clients = append(info.Clients, name)
+	}
+	return info
+}
+
+// SaveFile is the path to the file the plugin saves the game into.
+func (i *instance) SaveFile() string {
+	return path.Join("run", i.gameType, "save", i.id+".json")
+}
+
+// Destroy deletes the game, with its save.
 func (i *instance) Destroy() error {
-	_, err := i.cli.Destroy(context.TODO(), &game.RDestroyRequest{})
+	if i.host == nil {
+		// nothing has it loaded, so just take the save
+		err := os.Remove(i.SaveFile())
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	err := i.host.call(func(cli game.InstanceClient) error {
+		_, err := cli.Destroy(context.TODO(), &game.RDestroyRequest{Game: i.id})
+		return err
+	})
 	if err != nil {
 		code := status.Code(err)
 		if code == codes.Unavailable {
@@ -222,8 +381,28 @@ func (i *instance) Destroy() error {
 	return i.Shutdown()
 }
 
+// Shutdown takes the game out of its plugin process, which stops if the game
+// was the last in it.
 func (i *instance) Shutdown() error {
-	close(i.stopCh)
+	h := i.host
+	if h == nil {
+		// already stopped, or never started
+		return nil
+	}
+	i.host = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := h.call(func(cli game.InstanceClient) error {
+		_, err := cli.Unload(ctx, &game.RUnloadRequest{Game: i.id})
+		return err
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		// the process may be dead, but either way the game is gone from it
+		i.log.Warn().Err(err).Msg("unload failed")
+	}
+	i.pool.release(h, i.id)
 
 	return nil
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"math/rand"
 	"os"
@@ -13,22 +14,104 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// minIdleTimeout is the shortest --idle-timeout that makes sense.
+const minIdleTimeout = time.Second
+
 func main() {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
 	pgames := flag.String("games", "", "games to load")
+	padminToken := flag.String("admin-token", os.Getenv("GOGOGO_ADMIN_TOKEN"), "token for the admin API, disabled if empty")
+	pidleTimeout := flag.Duration("idle-timeout", 60*time.Second, "drop clients that are silent for this long")
+	pbotDelay := flag.Duration("bot-delay", time.Second, "how long bots wait before each move")
+	pcompleteAfter := flag.Duration("complete-after", time.Minute, "how long won games are kept before being archived")
+	parchiveRetention := flag.Duration("archive-retention", 0, "how long archived games are kept, forever if zero")
+	ptokens := flag.String("tokens", "", "file of API tokens, as \"name token\" lines")
+	paccounts := flag.String("accounts", "", "file of user accounts, e.g. run/accounts.json, enables accounts")
+	ptlsCert := flag.String("tls-cert", "", "TLS certificate file, enables TLS on the gateways")
+	ptlsKey := flag.String("tls-key", "", "TLS key file")
+	ppluginProcs := flag.Int("plugin-procs", 0, "most plugin processes per game type, sharing the games, or 0 for one per game")
+	ptlsDev := flag.Bool("tls-dev", false, "use TLS with a self-signed certificate, written to run/dev-cert.pem")
+	phookAllow := flag.String("hook-allow", "", "CIDRs that webhooks can be sent to, even if private")
+	phookDeny := flag.String("hook-deny", "", "CIDRs that webhooks can't be sent to, as well as private ones")
 	flag.Parse()
 
+	if *pidleTimeout < minIdleTimeout {
+		// clients are pinged at a third of it, and it can't be turned off
+		log.Error().Msgf("idle timeout must be at least %v", minIdleTimeout)
+		os.Exit(1)
+	}
+
 	games := strings.Split(*pgames, ",")
 
 	rand.Seed(time.Now().Unix())
 
-	server := NewServer(games)
+	var tokens map[string]string
+	if *ptokens != "" {
+		var err error
+		tokens, err = loadTokens(*ptokens)
+		if err != nil {
+			log.Error().Err(err).Msg("cannot load tokens")
+			os.Exit(1)
+		}
+	}
+
+	var accounts *accountStore
+	if *paccounts != "" {
+		// token users can't be taken as account names
+		var names []string
+		for _, name := range tokens {
+			names = append(names, name)
+		}
+		var err error
+		accounts, err = loadAccounts(*paccounts, names)
+		if err != nil {
+			log.Error().Err(err).Msg("cannot load accounts")
+			os.Exit(1)
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if *ptlsDev {
+		var err error
+		tlsConfig, err = devTLSConfig("run/dev-cert.pem")
+		if err != nil {
+			log.Error().Err(err).Msg("cannot make dev certificate")
+			os.Exit(1)
+		}
+		log.Warn().Msg("using self-signed certificate from run/dev-cert.pem")
+	} else if *ptlsCert != "" {
+		var err error
+		tlsConfig, err = loadTLSConfig(*ptlsCert, *ptlsKey)
+		if err != nil {
+			log.Error().Err(err).Msg("cannot load certificate")
+			os.Exit(1)
+		}
+	}
+
+	hookPolicy, err := parseHookPolicy(*phookAllow, *phookDeny)
+	if err != nil {
+		log.Error().Err(err).Msg("bad webhook policy")
+		os.Exit(1)
+	}
+
+	server := NewServer(games,
+		serverAdminToken(*padminToken),
+		serverAPITokens(tokens),
+		serverAccounts(accounts),
+		serverIdleTimeout(*pidleTimeout),
+		serverBotDelay(*pbotDelay),
+		serverCompleteAfter(*pcompleteAfter),
+		serverArchiveRetention(*parchiveRetention),
+		serverTLS(tlsConfig),
+		serverPluginProcs(*ppluginProcs),
+		serverHookPolicy(hookPolicy),
+	)
 
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
 
-	err := server.Run(ctx)
+	err = server.Run(ctx)
 	log.Info().Err(err).Msg("server return")
 	if err != nil {
 		os.Exit(1)
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+)
+
+// gameMeta is what the server itself knows about a game, as opposed to what
+// the plugin saves.
+type gameMeta struct {
+	Owner   string    `json:"owner"`
+	Created time.Time `json:"created"`
+	// Bots are the seats played by bots, mapped to the kind of bot
+	Bots map[string]string `json:"bots,omitempty"`
+	// Accounts are the seats linked to user accounts, mapped to the username
+	Accounts map[string]string `json:"accounts,omitempty"`
+	// ForkedFrom is the game this one was copied from, if any
+	ForkedFrom string `json:"forkedFrom,omitempty"`
+	// Imported is set for games started from a bundle
+	Imported bool `json:"imported,omitempty"`
+	// Webhooks are subscriptions to events in just this game
+	Webhooks []webhook `json:"webhooks,omitempty"`
+}
+
+func metaFileName(gameType, id string) string {
+	return path.Join("run", gameType, "meta", id+".json")
+}
+
+func loadMeta(gameType, id string) (gameMeta, error) {
+	meta := gameMeta{}
+	data, err := ioutil.ReadFile(metaFileName(gameType, id))
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(data, &meta)
+	return meta, err
+}
+
+func saveMeta(gameType, id string, meta gameMeta) error {
+	file := metaFileName(gameType, id)
+	err := os.MkdirAll(path.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+func removeMeta(gameType, id string) error {
+	err := os.Remove(metaFileName(gameType, id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
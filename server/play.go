@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// findSeat finds a game and a human player in it, for REST requests, which
+// have no connection to check them.
+func (s *server) findSeat(gameId, who string) (*instance, error) {
+	g, ok := s.games[gameId]
+	if !ok {
+		return nil, errGameNotFound
+	}
+	if g.state == nil {
+		return nil, errNoSeat
+	}
+	for _, pl := range g.state.Players {
+		if pl.Name == who {
+			if g.meta.Bots[who] != "" {
+				return nil, errNotAllowed
+			}
+			return g, nil
+		}
+	}
+	return nil, errNoSeat
+}
+
+func (s *server) doRestRequest(in restRequestMsg) {
+	g, err := s.findSeat(in.Game, in.Who)
+	if err != nil {
+		in.Rep <- restResult{Err: err}
+		return
+	}
+
+	h := g.host
+	go func() {
+		res, news := s.doUserRequestSub(g, h, requestFromUser{in.Game, in.Who, "", in.Cmd, in.Body})
+
+		s.coreCh <- afterRequest{g, in.Who, responseToUser{Body: res}, news, in.Rep}
+	}()
+}
+
+func (s *server) doPlayerState(in playerStateMsg) {
+	g, err := s.findSeat(in.Game, in.Who)
+	if err != nil {
+		in.Rep <- playerStateResult{Err: err}
+		return
+	}
+
+	for _, pState := range g.state.Players {
+		if pState.Name == in.Who {
+			update := playerView(g, pState, nil)
+			in.Rep <- playerStateResult{Update: &update}
+			return
+		}
+	}
+}
+
+// PlayRequest does what a player could do over a connection, but without one.
+// Other players get updates as usual.
+func (s *server) PlayRequest(gameId, who string, cmd []string, body []byte) (interface{}, error) {
+	resCh := make(chan restResult)
+	s.coreCh <- restRequestMsg{gameId, who, cmd, body, resCh}
+	res := <-resCh
+	return res.Res, res.Err
+}
+
+func (s *server) PlayerState(gameId, who string) (*game.GameUpdate, error) {
+	resCh := make(chan playerStateResult)
+	s.coreCh <- playerStateMsg{gameId, who, resCh}
+	res := <-resCh
+	return res.Update, res.Err
+}
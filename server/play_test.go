@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestFindSeat(t *testing.T) {
+	s := &server{games: map[string]*instance{}}
+
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Bots: map[string]string{"robo": "random"}}
+	g.state = &game.RGameState{
+		Players: []*game.RPlayerState{{Name: "phil"}, {Name: "robo"}},
+	}
+	s.games[g.id] = g
+
+	if g2, err := s.findSeat("g1", "phil"); err != nil || g2 != g {
+		t.Errorf("expected game, got %v", err)
+	}
+	if _, err := s.findSeat("g2", "phil"); err != errGameNotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := s.findSeat("g1", "bob"); err != errNoSeat {
+		t.Errorf("expected no seat, got %v", err)
+	}
+	if _, err := s.findSeat("g1", "robo"); err != errNotAllowed {
+		t.Errorf("expected not allowed for bot, got %v", err)
+	}
+}
+
+func TestPlayPlay_tooBig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &server{}
+	r := newWebRouter(s, log.Logger)
+
+	// never gets as far as the core
+	body := `{"command":"` + strings.Repeat("A", maxCommandSize) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/games/g1/play", strings.NewReader(body))
+	req.Header.Set(codeHeader, encodeConnectString("g1", "phil"))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %d", w.Code)
+	}
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/undeconstructed/gogogo/game"
+	"google.golang.org/grpc"
+)
+
+// pluginPool is the plugin processes for one game type. Each process can host
+// any number of games, and the pool puts each new game into one of them,
+// starting a new process if there are fewer than the limit. With no limit,
+// every game gets a process of its own.
+type pluginPool struct {
+	gameType string
+	// most processes to run, or 0 for one per game
+	max int
+
+	mu    sync.Mutex
+	hosts []*pluginHost
+	// for naming bind files
+	next int
+
+	log zerolog.Logger
+}
+
+func newPluginPool(gameType string, max int) *pluginPool {
+	return &pluginPool{
+		gameType: gameType,
+		max:      max,
+		log:      log.With().Str("pool", gameType).Logger(),
+	}
+}
+
+// pluginHost is one plugin process, and the games that are assigned to it.
+type pluginHost struct {
+	// held for reading while calling the process, and for writing while the
+	// process is being replaced, so that nothing is lost in the old one
+	swap sync.RWMutex
+
+	mu sync.Mutex
+	// relative bind file
+	bind string
+	// context that the process lives in
+	ctx    context.Context
+	cancel context.CancelFunc
+	proc   *process
+	conn   *grpc.ClientConn
+	cli    game.InstanceClient
+	// ids of games assigned here, true once they're in the process
+	games map[string]bool
+}
+
+// acquire assigns a game to a process, which will be running.
+func (p *pluginPool) acquire(ctx context.Context, id string) (*pluginHost, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var h *pluginHost
+	if p.max == 0 || len(p.hosts) < p.max {
+		// starting a process holds up the pool, but it's only a few seconds at most
+		h = &pluginHost{
+			bind:  p.nextBind(),
+			games: map[string]bool{},
+		}
+		err := p.startHost(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		p.hosts = append(p.hosts, h)
+	} else {
+		for _, h1 := range p.hosts {
+			if h == nil || h1.load() < h.load() {
+				h = h1
+			}
+		}
+	}
+
+	h.mu.Lock()
+	h.games[id] = false
+	h.mu.Unlock()
+
+	return h, nil
+}
+
+// release takes a game out of a process, which is stopped if that was its
+// last game.
+func (p *pluginPool) release(h *pluginHost, id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h.mu.Lock()
+	delete(h.games, id)
+	empty := len(h.games) == 0
+	h.mu.Unlock()
+
+	if !empty {
+		return
+	}
+
+	for n, h1 := range p.hosts {
+		if h1 == h {
+			p.hosts = append(p.hosts[:n], p.hosts[n+1:]...)
+			break
+		}
+	}
+	h.stop()
+}
+
+// holds says whether a host is in the pool, with games. This needs the pool
+// lock.
+func (p *pluginPool) holds(h *pluginHost) bool {
+	for _, h1 := range p.hosts {
+		if h1 == h {
+			return h.load() > 0
+		}
+	}
+	return false
+}
+
+// hostOf finds the process that a game is in, if it is in one.
+func (p *pluginPool) hostOf(id string) *pluginHost {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, h := range p.hosts {
+		h.mu.Lock()
+		in := h.games[id]
+		h.mu.Unlock()
+		if in {
+			return h
+		}
+	}
+	return nil
+}
+
+// restart stops a process and starts a new one in its place, that reloads all
+// the games that were in it, and the one given, which may not have been, and
+// gives back that one's state. Calls to the process wait meanwhile, as for an
+// upgrade, so none get to the new one before the games are in it.
+func (p *pluginPool) restart(h *pluginHost, id string) (*game.RGameState, error) {
+	h.swap.Lock()
+	defer h.swap.Unlock()
+
+	h.stop()
+
+	h.mu.Lock()
+	proc := h.proc
+	h.mu.Unlock()
+	if proc != nil {
+		// the old process must be gone before anything binds the same path
+		select {
+		case <-proc.Done():
+		case <-time.After(5 * time.Second):
+			return nil, errors.New("old process did not stop")
+		}
+	}
+
+	h.mu.Lock()
+	ctx := h.ctx
+	h.mu.Unlock()
+
+	err := p.startHost(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	var others []string
+	for other, in := range h.games {
+		if in && other != id {
+			others = append(others, other)
+		}
+	}
+	cli := h.cli
+	h.mu.Unlock()
+
+	for _, other := range others {
+		_, err := cli.Load(ctx, &game.RLoadRequest{Id: other})
+		if err != nil {
+			p.log.Err(err).Msgf("cannot reload game: %s", other)
+		}
+	}
+
+	res, err := cli.Load(ctx, &game.RLoadRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	h.ready(id)
+
+	return res.State, nil
+}
+
+// upgrade replaces every process with a new one, from whatever the binary
+// is now, each loading the games from the old one from their saves. Calls to
+// each process wait while it's being replaced. If a new process can't take
+// the games then the old one is kept, and the upgrade stops there.
+func (p *pluginPool) upgrade() error {
+	p.mu.Lock()
+	hosts := append([]*pluginHost{}, p.hosts...)
+	p.mu.Unlock()
+
+	for _, h := range hosts {
+		err := p.upgradeHost(h)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *pluginPool) upgradeHost(h *pluginHost) error {
+	h.mu.Lock()
+	ctx := h.ctx
+	h.mu.Unlock()
+
+	p.mu.Lock()
+	bind := p.nextBind()
+	p.mu.Unlock()
+
+	// the old process keeps going while the new one starts
+	pro, conn, cancel, err := p.startProcess(ctx, bind)
+	if err != nil {
+		return err
+	}
+	cli := game.NewInstanceClient(conn)
+
+	h.swap.Lock()
+	defer h.swap.Unlock()
+
+	// with nothing calling the process, games not in it yet will be put into
+	// the new one, when they are
+	h.mu.Lock()
+	var ids []string
+	for id, in := range h.games {
+		if in {
+			ids = append(ids, id)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, id := range ids {
+		_, err := cli.Load(ctx, &game.RLoadRequest{Id: id})
+		if err != nil {
+			conn.Close()
+			cancel()
+			return fmt.Errorf("cannot load %s in new process: %w", id, err)
+		}
+	}
+
+	// the host may have been released while the new process started, and then
+	// nothing would ever stop the new one. holding the pool lock means that it
+	// can't be released now until the new one is in place, to be stopped.
+	p.mu.Lock()
+	live := p.holds(h)
+	if live {
+		h.mu.Lock()
+		oldConn, oldCancel := h.conn, h.cancel
+		h.bind = bind
+		h.cancel = cancel
+		h.proc = pro
+		h.conn = conn
+		h.cli = cli
+		h.mu.Unlock()
+
+		// the old process has the games too, but nothing will call it now
+		oldConn.Close()
+		oldCancel()
+	}
+	p.mu.Unlock()
+
+	if !live {
+		conn.Close()
+		cancel()
+		return nil
+	}
+
+	p.log.Info().Msgf("upgraded to %s with %d games", bind, len(ids))
+
+	return nil
+}
+
+// describe asks the binary as it is now what it is, and for its assets.
+func (p *pluginPool) describe(ctx context.Context) (game.Description, map[string]*asset, error) {
+	p.mu.Lock()
+	bind := p.nextBind()
+	p.mu.Unlock()
+
+	return describeType(ctx, p.gameType, bind)
+}
+
+// startHost starts the process for a host, replacing any old one, which should
+// have been stopped.
+func (p *pluginPool) startHost(ctx context.Context, h *pluginHost) error {
+	h.mu.Lock()
+	bind := h.bind
+	h.mu.Unlock()
+
+	pro, conn, cancel, err := p.startProcess(ctx, bind)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.ctx = ctx
+	h.cancel = cancel
+	h.proc = pro
+	h.conn = conn
+	h.cli = game.NewInstanceClient(conn)
+	h.mu.Unlock()
+
+	return nil
+}
+
+// startProcess starts a plugin process, which runs until the cancel func is
+// called, or the context is done.
+func (p *pluginPool) startProcess(ctx context.Context, bind string) (*process, *grpc.ClientConn, context.CancelFunc, error) {
+	// run dir
+	dir := "./" + path.Join("run", p.gameType)
+	// relative binary path
+	bin := "./bin"
+
+	p.log.Info().Msgf("will bind to: %s", bind)
+
+	pro := newProcess(dir, bin, bind)
+
+	ctx1, cancel := context.WithCancel(ctx)
+
+	conn, err := pro.Start(ctx1)
+	if err != nil {
+		cancel()
+		return nil, nil, nil, err
+	}
+
+	return pro, conn, cancel, nil
+}
+
+// nextBind names a new bind file. This needs the pool lock.
+func (p *pluginPool) nextBind() string {
+	p.next++
+	return path.Join("bind", fmt.Sprintf("%s-%d.pipe", p.gameType, p.next))
+}
+
+// stop stops the process.
+func (h *pluginHost) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.conn != nil {
+		h.conn.Close()
+	}
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
+
+// call calls the process, waiting if it's being replaced.
+func (h *pluginHost) call(f func(cli game.InstanceClient) error) error {
+	h.swap.RLock()
+	defer h.swap.RUnlock()
+
+	h.mu.Lock()
+	cli := h.cli
+	h.mu.Unlock()
+
+	return f(cli)
+}
+
+// ready marks a game as in the process.
+func (h *pluginHost) ready(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.games[id]; ok {
+		h.games[id] = true
+	}
+}
+
+// load is how many games are in the process.
+func (h *pluginHost) load() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.games)
+}
+
+// Health is a simple description of whether the process can be reached.
+func (h *pluginHost) Health() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conn == nil {
+		return "none"
+	}
+	return h.conn.GetState().String()
+}
+
+// Pid is the OS process ID, or 0 if it's not running.
+func (h *pluginHost) Pid() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.proc == nil {
+		return 0
+	}
+	return h.proc.Pid()
+}
+
+// Path is the bind path as seen from the server.
+func (h *pluginHost) Path() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.proc == nil {
+		return ""
+	}
+	return h.proc.Path()
+}
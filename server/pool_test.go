@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestPluginPool_acquire(t *testing.T) {
+	p := newPluginPool("go", 2)
+	// as if the processes were started already
+	h1 := &pluginHost{games: map[string]bool{"g1": true, "g2": true}}
+	h2 := &pluginHost{games: map[string]bool{"g3": true}}
+	p.hosts = []*pluginHost{h1, h2}
+
+	h, err := p.acquire(context.Background(), "g4")
+	if err != nil || h != h2 {
+		t.Fatalf("expected least loaded host, got %v", err)
+	}
+	if _, ok := h2.games["g4"]; !ok {
+		t.Errorf("expected game assigned")
+	}
+
+	p.release(h1, "g1")
+	if len(p.hosts) != 2 {
+		t.Errorf("expected host kept while it has games")
+	}
+	p.release(h1, "g2")
+	if len(p.hosts) != 1 || p.hosts[0] != h2 {
+		t.Errorf("expected empty host gone, got %d", len(p.hosts))
+	}
+}
+
+// TestMain lets the test binary be a plugin too, for tests that need real
+// plugin processes.
+func TestMain(m *testing.M) {
+	if version := os.Getenv("GOGOGO_TEST_PLUGIN"); version != "" {
+		runTestPlugin(version, os.Getenv("GOGOGO_TEST_PLUGIN_NAME"))
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// testGame counts the moves made in it.
+type testGame struct {
+	Count int `json:"count"`
+}
+
+func (g *testGame) AddPlayer(name string, options map[string]interface{}) error { return nil }
+func (g *testGame) Start() error                                                { return nil }
+
+func (g *testGame) Play(player string, c game.Command) (game.PlayResult, error) {
+	g.Count++
+	return game.PlayResult{Response: g.Count}, nil
+}
+
+func (g *testGame) GetGameState() game.GameState {
+	return game.GameState{Status: game.StatusInProgress, TurnNumber: g.Count}
+}
+
+func (g *testGame) WriteOut(w io.Writer) error {
+	return json.NewEncoder(w).Encode(g)
+}
+
+func runTestPlugin(version, name string) {
+	desc := game.Description{Name: "test", DisplayName: name, MinPlayers: 1, MaxPlayers: 2, Version: version}
+	assets := fstest.MapFS{"web/index.html": {Data: []byte(name)}}
+	game.GRPCMain(desc, assets, func(map[string]interface{}) (game.Game, error) {
+		return &testGame{}, nil
+	}, func(r io.Reader) (game.Game, error) {
+		g := &testGame{}
+		return g, json.NewDecoder(r).Decode(g)
+	})
+}
+
+// setTestPlugin makes run/test/bin, in a temp dir, be this test binary, which
+// is then a plugin of some version, and with some name.
+func setTestPlugin(t *testing.T, version, name string) {
+	if _, err := os.Stat("run/test/bin"); os.IsNotExist(err) {
+		exe, err := os.Executable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.MkdirAll("run/test/bind", 0755)
+		os.MkdirAll("run/test/save", 0755)
+		if err := os.Symlink(exe, "run/test/bin"); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			os.Unsetenv("GOGOGO_TEST_PLUGIN")
+			os.Unsetenv("GOGOGO_TEST_PLUGIN_NAME")
+		})
+	}
+	os.Setenv("GOGOGO_TEST_PLUGIN", version)
+	os.Setenv("GOGOGO_TEST_PLUGIN_NAME", name)
+}
+
+func TestPluginPool_replace(t *testing.T) {
+	inTempDir(t)
+	setTestPlugin(t, "1", "one")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// both games in one process
+	p := newPluginPool("test", 1)
+	var h *pluginHost
+	for _, id := range []string{"g1", "g2"} {
+		var err error
+		h, err = p.acquire(ctx, id)
+		if err != nil {
+			t.Fatalf("acquire %s: %v", id, err)
+		}
+		err = h.call(func(cli game.InstanceClient) error {
+			_, err := cli.Init(ctx, &game.RInitRequest{Id: id, Options: []byte("{}")})
+			return err
+		})
+		if err != nil {
+			t.Fatalf("init %s: %v", id, err)
+		}
+		h.ready(id)
+	}
+	defer p.release(h, "g1")
+	defer p.release(h, "g2")
+
+	play := func(id string) (int, error) {
+		var res *game.RPlayResponse
+		err := h.call(func(cli game.InstanceClient) (err error) {
+			res, err = cli.Play(ctx, &game.RPlayRequest{Game: id, Command: "move"})
+			return
+		})
+		if err != nil {
+			return 0, err
+		}
+		return int(res.State.TurnNumber), nil
+	}
+
+	// plays g2 while the process is replaced, counting moves
+	whilePlaying := func(replace func() error) int {
+		done := make(chan struct{})
+		played := make(chan int)
+		go func() {
+			n := 0
+			for {
+				select {
+				case <-done:
+					played <- n
+					return
+				default:
+				}
+				if _, err := play("g2"); err != nil {
+					t.Errorf("play during replace: %v", err)
+				} else {
+					n++
+				}
+			}
+		}()
+		if err := replace(); err != nil {
+			t.Fatalf("replace: %v", err)
+		}
+		close(done)
+		return <-played
+	}
+
+	if n, err := play("g1"); n != 1 || err != nil {
+		t.Fatalf("expected g1 at 1, got %d %v", n, err)
+	}
+
+	for name, replace := range map[string]func() error{
+		"upgrade": p.upgrade,
+		// the game being played is the one restarted
+		"restart": func() error {
+			_, err := p.restart(h, "g2")
+			return err
+		},
+	} {
+		pid := h.Pid()
+		g1, _ := play("g1")
+		g2, _ := play("g2")
+		n := whilePlaying(replace)
+		if h.Pid() == pid || h.Pid() == 0 {
+			t.Errorf("%s: expected new process", name)
+		}
+		// nothing was lost
+		if m, err := play("g1"); m != g1+1 || err != nil {
+			t.Errorf("%s: expected g1 at %d, got %d %v", name, g1+1, m, err)
+		}
+		if m, err := play("g2"); m != g2+n+1 || err != nil {
+			t.Errorf("%s: expected g2 at %d, got %d %v", name, g2+n+1, m, err)
+		}
+	}
+}
+
+func TestAdminUpgrade(t *testing.T) {
+	inTempDir(t)
+	setTestPlugin(t, "1", "one")
+
+	s := &server{
+		gameTypes: []string{"test"},
+		types:     map[string]game.Description{"test": {Name: "test", DisplayName: "one", Version: "1"}},
+		assets:    map[string]map[string]*asset{"test": {}},
+	}
+	runTestCore(s)
+	defer close(s.coreCh)
+
+	if err := s.AdminUpgrade("chess"); err != errTypeNotFound {
+		t.Errorf("expected type not found, got %v", err)
+	}
+
+	// saves wouldn't load
+	setTestPlugin(t, "2", "two")
+	if err := s.AdminUpgrade("test"); game.Code(err) != game.StatusConflict {
+		t.Errorf("expected conflict, got %v", err)
+	}
+	if s.types["test"].DisplayName != "one" {
+		t.Errorf("expected description kept")
+	}
+
+	setTestPlugin(t, "1", "three")
+	if err := s.AdminUpgrade("test"); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if desc := s.types["test"]; desc.DisplayName != "three" || desc.Name != "test" {
+		t.Errorf("expected new description, got %v", desc)
+	}
+	if a, ok := s.asset("test", "web/index.html"); !ok || string(a.data) != "three" {
+		t.Errorf("expected new assets")
+	}
+}
+
+func TestPluginPool_upgradeReleased(t *testing.T) {
+	inTempDir(t)
+	setTestPlugin(t, "1", "one")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newPluginPool("test", 1)
+	h, err := p.acquire(ctx, "g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proc := h.proc
+
+	// gone before the upgrade gets to it
+	p.release(h, "g1")
+	if err := p.upgradeHost(h); err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if h.proc != proc {
+		t.Errorf("expected no new process for a released host")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -23,6 +24,12 @@ type process struct {
 	bind string
 
 	shouldRestart bool
+
+	// pid of the running OS process, or 0
+	pidLock sync.Mutex
+	pid     int
+	// closed once the process has been stopped and has exited
+	doneCh chan struct{}
 }
 
 type processOption func(*process)
@@ -38,10 +45,11 @@ func processShouldRestart(b bool) processOption {
 func newProcess(dir, file, bind string, opts ...processOption) *process {
 	log := log.With().Str("process", bind).Logger()
 	p := &process{
-		log:  log,
-		dir:  dir,
-		file: file,
-		bind: bind,
+		log:    log,
+		dir:    dir,
+		file:   file,
+		bind:   bind,
+		doneCh: make(chan struct{}),
 	}
 	for _, o := range opts {
 		o(p)
@@ -53,32 +61,48 @@ func (p *process) Start(ctx context.Context) (*grpc.ClientConn, error) {
 	ch := make(chan string)
 
 	isStop := false
+	// whether there's no OS process, and none coming
+	exited := false
 	fails := 0
 
 	pctx, pcancel := context.WithCancel(ctx)
 
+	start := func() {
+		err := p.start(pctx, ch)
+		if err != nil {
+			p.log.Err(err).Msg("process did not start")
+			ch <- "term"
+		}
+	}
+
 	go func() {
 		for m := range ch {
 			switch m {
 			case "start":
-				go p.start(pctx, ch)
+				go start()
 			case "stop":
 				if !isStop {
 					isStop = true
 					pcancel()
+					if exited {
+						// there'll be no term to wait for
+						close(p.doneCh)
+					}
 				}
 			case "term":
-				if !isStop {
-					if p.shouldRestart {
+				if isStop {
+					close(p.doneCh)
+				} else {
+					if p.shouldRestart && fails < 2 {
 						fails++
-						if fails >= 3 {
-							// give it up
+						go start()
+					} else {
+						if p.shouldRestart {
 							p.log.Error().Msg("process keeps dying")
-							return
+						} else {
+							p.log.Error().Msg("process has died")
 						}
-						go p.start(pctx, ch)
-					} else {
-						p.log.Error().Msg("process has died")
+						exited = true
 					}
 				}
 			}
@@ -125,6 +149,7 @@ func (p *process) start(ctx context.Context, ch chan<- string) error {
 	if err != nil {
 		return fmt.Errorf("failed to start process: %w", err)
 	}
+	p.setPid(cmd.Process.Pid)
 
 	go func() {
 		r := bufio.NewReader(stdout)
@@ -153,6 +178,7 @@ func (p *process) start(ctx context.Context, ch chan<- string) error {
 		if err != nil {
 			p.log.Err(err).Msgf("process ended with error")
 		}
+		p.setPid(0)
 		remoteBind := path.Join(p.dir, p.bind)
 		err = os.Remove(remoteBind)
 		if err != nil {
@@ -163,3 +189,26 @@ func (p *process) start(ctx context.Context, ch chan<- string) error {
 
 	return nil
 }
+
+func (p *process) setPid(pid int) {
+	p.pidLock.Lock()
+	defer p.pidLock.Unlock()
+	p.pid = pid
+}
+
+// Pid is the OS process ID, or 0 if the process is not running.
+func (p *process) Pid() int {
+	p.pidLock.Lock()
+	defer p.pidLock.Unlock()
+	return p.pid
+}
+
+// Path is the bind path as seen from the parent.
+func (p *process) Path() string {
+	return path.Join(p.dir, p.bind)
+}
+
+// Done is closed after the process has been told to stop, and has exited.
+func (p *process) Done() <-chan struct{} {
+	return p.doneCh
+}
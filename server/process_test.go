@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestProcess_doneAfterDeath(t *testing.T) {
+	// exits at once, so it's dead before anything stops it
+	p := newProcess(t.TempDir(), "false", "bind/x.pipe")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err := p.Start(ctx)
+	if err == nil {
+		t.Fatal("expected no connection")
+	}
+
+	select {
+	case <-p.Done():
+	case <-time.After(2 * time.Second):
+		t.Errorf("expected done after dying")
+	}
+}
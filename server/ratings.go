@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"time"
+)
+
+const (
+	// initialRating is where everyone starts
+	initialRating = 1500
+	// ratingK is how much one game can move a rating
+	ratingK = 32
+)
+
+// resultsFile is where finished games are recorded.
+var resultsFile = path.Join("run", "results.json")
+
+// GameResult is the record of a finished game. Players are named by account,
+// or as bot:<kind> for bots, as seat names are only names within a game.
+type GameResult struct {
+	Game    string    `json:"game"`
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Winner  string    `json:"winner"`
+	Players []string  `json:"players"`
+}
+
+// Rating is a player's standing in one game type.
+type Rating struct {
+	Player string `json:"player"`
+	Rating int    `json:"rating"`
+	Games  int    `json:"games"`
+	Wins   int    `json:"wins"`
+}
+
+// HistoryEntry is a game in a player's history, with what it did to the
+// rating.
+type HistoryEntry struct {
+	GameResult
+	Won    bool `json:"won"`
+	Before int  `json:"before"`
+	After  int  `json:"after"`
+}
+
+// resultStore keeps every result, and works ratings out from them when asked,
+// so that leaderboards can be for any period, e.g. a month of a league.
+type resultStore struct {
+	file    string
+	results []GameResult
+}
+
+func loadResults(file string) (*resultStore, error) {
+	r := &resultStore{file: file}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &r.results)
+	return r, err
+}
+
+// add records a result, once per game.
+func (r *resultStore) add(res GameResult) error {
+	for _, old := range r.results {
+		if old.Game == res.Game {
+			return nil
+		}
+	}
+	r.results = append(r.results, res)
+
+	err := os.MkdirAll(path.Dir(r.file), 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r.results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.file, data, 0644)
+}
+
+// replay runs through the results for a game type, in order, from some time,
+// calling f with the ratings before and after each game.
+func (r *resultStore) replay(gameType string, since time.Time, f func(res GameResult, before, after map[string]float64)) map[string]*Rating {
+	ratings := map[string]float64{}
+	stats := map[string]*Rating{}
+
+	for _, res := range r.results {
+		if res.Type != gameType || res.Time.Before(since) {
+			continue
+		}
+		if !distinct(res.Players) {
+			// from before such games were skipped, and can't be rated
+			continue
+		}
+
+		before := map[string]float64{}
+		for _, p := range res.Players {
+			if _, ok := ratings[p]; !ok {
+				ratings[p] = initialRating
+			}
+			before[p] = ratings[p]
+			if stats[p] == nil {
+				stats[p] = &Rating{Player: p}
+			}
+			stats[p].Games++
+		}
+		if stats[res.Winner] != nil {
+			stats[res.Winner].Wins++
+		}
+
+		after := eloUpdate(before, res.Winner)
+		for p, v := range after {
+			ratings[p] = v
+		}
+
+		if f != nil {
+			f(res, before, after)
+		}
+	}
+
+	for p, st := range stats {
+		st.Rating = int(math.Round(ratings[p]))
+	}
+	return stats
+}
+
+// eloUpdate works out new ratings after a game. The winner is taken to have
+// beaten each other player, with K shared out so that bigger games don't move
+// ratings more.
+func eloUpdate(before map[string]float64, winner string) map[string]float64 {
+	after := map[string]float64{}
+	for p, v := range before {
+		after[p] = v
+	}
+	if _, ok := before[winner]; !ok || len(before) < 2 {
+		return after
+	}
+
+	k := ratingK / float64(len(before)-1)
+	for p, v := range before {
+		if p == winner {
+			continue
+		}
+		expected := 1 / (1 + math.Pow(10, (v-before[winner])/400))
+		delta := k * (1 - expected)
+		after[winner] += delta
+		after[p] -= delta
+	}
+	return after
+}
+
+// Leaderboard is everyone who played a game type, best first.
+func (r *resultStore) Leaderboard(gameType string, since time.Time) []Rating {
+	list := []Rating{}
+	for _, st := range r.replay(gameType, since, nil) {
+		list = append(list, *st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Rating != list[j].Rating {
+			return list[i].Rating > list[j].Rating
+		}
+		return list[i].Player < list[j].Player
+	})
+	return list
+}
+
+// History is every game a player played, of a game type, newest first.
+func (r *resultStore) History(gameType, player string, since time.Time) []HistoryEntry {
+	list := []HistoryEntry{}
+	r.replay(gameType, since, func(res GameResult, before, after map[string]float64) {
+		if b, ok := before[player]; ok {
+			list = append(list, HistoryEntry{
+				GameResult: res,
+				Won:        res.Winner == player,
+				Before:     int(math.Round(b)),
+				After:      int(math.Round(after[player])),
+			})
+		}
+	})
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
+
+// ratedName is who a seat counts as, for ratings, or "" if it's no one who
+// can be rated, i.e. a seat without an account.
+func ratedName(g *instance, seat string) string {
+	if acc := g.meta.Accounts[seat]; acc != "" {
+		return acc
+	}
+	if bot := g.meta.Bots[seat]; bot != "" {
+		return "bot:" + bot
+	}
+	return ""
+}
+
+// distinct says whether no name is in a list twice.
+func distinct(names []string) bool {
+	seen := map[string]bool{}
+	for _, n := range names {
+		if seen[n] {
+			return false
+		}
+		seen[n] = true
+	}
+	return true
+}
+
+// recordResult records the result of a game that has just been won. Forks and
+// imports aren't rated, because they didn't start from the beginning here, and
+// nor are games with seats that aren't anyone, or two seats that are the same
+// one, e.g. two bots of one kind.
+func (s *server) recordResult(g *instance) {
+	if s.results == nil || g.meta.ForkedFrom != "" || g.meta.Imported {
+		return
+	}
+
+	res := GameResult{
+		Game:   g.id,
+		Type:   g.gameType,
+		Time:   time.Now(),
+		Winner: ratedName(g, g.state.Winner),
+	}
+	for _, pl := range g.state.Players {
+		name := ratedName(g, pl.Name)
+		if name == "" {
+			return
+		}
+		res.Players = append(res.Players, name)
+	}
+	if !distinct(res.Players) {
+		return
+	}
+
+	err := s.results.add(res)
+	if err != nil {
+		g.log.Err(err).Msg("cannot record result")
+	}
+}
+
+func (s *server) doLeaderboard(in leaderboardMsg) {
+	if s.results == nil {
+		in.Rep <- nil
+		return
+	}
+	in.Rep <- s.results.Leaderboard(in.Type, in.Since)
+}
+
+func (s *server) doHistory(in historyMsg) {
+	if s.results == nil {
+		in.Rep <- nil
+		return
+	}
+	in.Rep <- s.results.History(in.Type, in.Player, in.Since)
+}
+
+func (s *server) Leaderboard(gameType string, since time.Time) []Rating {
+	resCh := make(chan []Rating)
+	s.coreCh <- leaderboardMsg{gameType, since, resCh}
+	return <-resCh
+}
+
+func (s *server) History(gameType, player string, since time.Time) []HistoryEntry {
+	resCh := make(chan []HistoryEntry)
+	s.coreCh <- historyMsg{gameType, player, since, resCh}
+	return <-resCh
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestEloUpdate(t *testing.T) {
+	after := eloUpdate(map[string]float64{"a": 1500, "b": 1500}, "a")
+	if after["a"] != 1516 || after["b"] != 1484 {
+		t.Errorf("bad ratings: %v", after)
+	}
+
+	// beating someone much better is worth more
+	after = eloUpdate(map[string]float64{"a": 1500, "b": 1900}, "a")
+	if d := after["a"] - 1500; d < 28 {
+		t.Errorf("upset should be worth more: %v", d)
+	}
+
+	// the total doesn't change
+	after = eloUpdate(map[string]float64{"a": 1500, "b": 1600, "c": 1400}, "c")
+	if sum := after["a"] + after["b"] + after["c"]; sum < 4499.99 || sum > 4500.01 {
+		t.Errorf("bad sum: %v", sum)
+	}
+
+	// nobody to beat
+	after = eloUpdate(map[string]float64{"a": 1500}, "a")
+	if after["a"] != 1500 {
+		t.Errorf("bad solo rating: %v", after)
+	}
+}
+
+func TestResultStore(t *testing.T) {
+	file := path.Join(t.TempDir(), "results.json")
+	r, err := loadResults(file)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	r.add(GameResult{Game: "1", Type: "go", Time: day(1), Winner: "a", Players: []string{"a", "b"}})
+	r.add(GameResult{Game: "2", Type: "go", Time: day(2), Winner: "a", Players: []string{"a", "c"}})
+	r.add(GameResult{Game: "3", Type: "go", Time: day(3), Winner: "b", Players: []string{"a", "b"}})
+	r.add(GameResult{Game: "4", Type: "rummy", Time: day(3), Winner: "c", Players: []string{"c", "b"}})
+	// the same game again is ignored
+	r.add(GameResult{Game: "3", Type: "go", Time: day(3), Winner: "a", Players: []string{"a", "b"}})
+
+	board := r.Leaderboard("go", time.Time{})
+	if len(board) != 3 || board[0].Player != "a" || board[0].Games != 3 || board[0].Wins != 2 {
+		t.Errorf("bad leaderboard: %v", board)
+	}
+
+	board = r.Leaderboard("go", day(3))
+	if len(board) != 2 || board[0].Player != "b" || board[0].Rating != 1516 {
+		t.Errorf("bad leaderboard since: %v", board)
+	}
+
+	history := r.History("go", "b", time.Time{})
+	if len(history) != 2 || history[0].Game != "3" || !history[0].Won || history[0].After <= history[0].Before {
+		t.Errorf("bad history: %v", history)
+	}
+
+	// saved
+	r2, err := loadResults(file)
+	if err != nil || len(r2.results) != 4 {
+		t.Errorf("bad reload: %v %v", r2, err)
+	}
+}
+
+func TestRecordResult_copies(t *testing.T) {
+	results, err := loadResults(path.Join(t.TempDir(), "results.json"))
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	s := &server{results: results}
+
+	won := func(id string, meta gameMeta) *instance {
+		g := newInstance("go", id)
+		g.meta = meta
+		g.state = &game.RGameState{
+			Status:  string(game.StatusWon),
+			Winner:  "a",
+			Players: []*game.RPlayerState{{Name: "a"}, {Name: "b"}},
+		}
+		return g
+	}
+
+	accounts := map[string]string{"a": "alice", "b": "bob"}
+	s.recordResult(won("g1", gameMeta{Accounts: accounts}))
+	s.recordResult(won("g2", gameMeta{Accounts: accounts, ForkedFrom: "g1"}))
+	s.recordResult(won("g3", gameMeta{Accounts: accounts, Imported: true}))
+
+	board := results.Leaderboard("go", time.Time{})
+	if len(board) != 2 || board[0].Player != "alice" || board[0].Games != 1 {
+		t.Errorf("expected only the original rated, got %v", board)
+	}
+}
+
+func TestRecordResult_names(t *testing.T) {
+	results, err := loadResults(path.Join(t.TempDir(), "results.json"))
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	s := &server{results: results}
+
+	won := func(id string, meta gameMeta) *instance {
+		g := newInstance("go", id)
+		g.meta = meta
+		g.state = &game.RGameState{
+			Status:  string(game.StatusWon),
+			Winner:  "a",
+			Players: []*game.RPlayerState{{Name: "a"}, {Name: "b"}},
+		}
+		return g
+	}
+
+	// a seat without an account is no one in particular
+	s.recordResult(won("g1", gameMeta{}))
+	s.recordResult(won("g2", gameMeta{Accounts: map[string]string{"a": "alice"}}))
+	// two seats that are the same player
+	s.recordResult(won("g3", gameMeta{Accounts: map[string]string{"a": "alice", "b": "alice"}}))
+	s.recordResult(won("g4", gameMeta{Bots: map[string]string{"a": "random", "b": "random"}}))
+	if len(results.results) != 0 {
+		t.Errorf("expected nothing rated, got %v", results.results)
+	}
+
+	s.recordResult(won("g5", gameMeta{Accounts: map[string]string{"b": "bob"}, Bots: map[string]string{"a": "random"}}))
+	board := results.Leaderboard("go", time.Time{})
+	if len(board) != 2 || board[0].Player != "bot:random" || board[1].Player != "bob" {
+		t.Errorf("bad leaderboard: %v", board)
+	}
+
+	// results with a player twice, from before, are left out
+	results.results = append(results.results, GameResult{Game: "old", Type: "go", Winner: "bob", Players: []string{"bob", "bob"}})
+	if board := results.Leaderboard("go", time.Time{}); board[1].Games != 1 {
+		t.Errorf("expected old result left out, got %v", board)
+	}
+}
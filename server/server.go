@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/undeconstructed/gogogo/comms"
 	"github.com/undeconstructed/gogogo/game"
@@ -15,7 +19,84 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func NewServer(gameTypes []string) *server {
+type serverOption func(*server)
+
+// serverAdminToken sets the token needed for the admin API. With no token the
+// admin API is disabled.
+func serverAdminToken(token string) serverOption {
+	return func(s *server) {
+		s.adminToken = token
+	}
+}
+
+// serverAPITokens sets the tokens, mapped to user names, that can be used to
+// create and delete games.
+func serverAPITokens(tokens map[string]string) serverOption {
+	return func(s *server) {
+		s.apiTokens = tokens
+	}
+}
+
+// serverIdleTimeout sets how long a client connection can be silent before it
+// is dropped. Clients are pinged well within this time.
+func serverIdleTimeout(d time.Duration) serverOption {
+	return func(s *server) {
+		s.idleTimeout = d
+	}
+}
+
+// serverBotDelay sets how long bots wait before each move.
+func serverBotDelay(d time.Duration) serverOption {
+	return func(s *server) {
+		s.botDelay = d
+	}
+}
+
+// serverAccounts enables user accounts.
+func serverAccounts(accounts *accountStore) serverOption {
+	return func(s *server) {
+		s.accounts = accounts
+	}
+}
+
+// serverTLS makes the gateways use TLS.
+func serverTLS(config *tls.Config) serverOption {
+	return func(s *server) {
+		s.tlsConfig = config
+	}
+}
+
+// serverCompleteAfter sets how long won games are kept, before being archived.
+func serverCompleteAfter(d time.Duration) serverOption {
+	return func(s *server) {
+		s.completeAfter = d
+	}
+}
+
+// serverArchiveRetention sets how long archived games are kept. With zero
+// they are kept forever.
+func serverArchiveRetention(d time.Duration) serverOption {
+	return func(s *server) {
+		s.archiveRetention = d
+	}
+}
+
+// serverPluginProcs sets how many plugin processes each game type can have,
+// with the games shared between them. With zero every game has its own.
+func serverPluginProcs(n int) serverOption {
+	return func(s *server) {
+		s.pluginProcs = n
+	}
+}
+
+// serverHookPolicy sets which addresses webhooks can be sent to.
+func serverHookPolicy(policy hookPolicy) serverOption {
+	return func(s *server) {
+		s.hookPolicy = policy
+	}
+}
+
+func NewServer(gameTypes []string, opts ...serverOption) *server {
 	games := map[string]*instance{}
 	for _, gt := range gameTypes {
 		saveDir := path.Join("run", gt, "save")
@@ -28,26 +109,133 @@ func NewServer(gameTypes []string) *server {
 			// use list of files as database, but don't actually load anything here
 			if strings.HasSuffix(fname, ".json") {
 				gameId := fname[:len(fname)-5]
-				games[gameId] = newInstance(gt, gameId)
+				i := newInstance(gt, gameId)
+				meta, err := loadMeta(gt, gameId)
+				if err != nil && !os.IsNotExist(err) {
+					log.Error().Err(err).Msgf("can't read meta for %s", gameId)
+				}
+				i.meta = meta
+				games[gameId] = i
 			}
 		}
 	}
 
+	webhooks, err := loadWebhooks(webhooksFile)
+	if err != nil {
+		log.Error().Err(err).Msg("can't read webhooks")
+	}
+
+	results, err := loadResults(resultsFile)
+	if err != nil {
+		log.Error().Err(err).Msg("can't read results")
+	}
+
 	coreCh := make(chan interface{}, 100)
-	return &server{
+	s := &server{
 		gameTypes: gameTypes,
 		games:     games,
 		coreCh:    coreCh,
+		webhooks:  webhooks,
+		results:   results,
+		archive:   loadArchiveIndex(gameTypes),
+
+		idleTimeout:   60 * time.Second,
+		botDelay:      time.Second,
+		completeAfter: time.Minute,
 	}
+	for _, o := range opts {
+		o(s)
+	}
+	for _, g := range games {
+		g.pool = s.pool(g.gameType)
+	}
+	return s
+}
+
+// newInstance makes an instance that will run in the type's plugin pool.
+func (s *server) newInstance(gameType string, id string) *instance {
+	i := newInstance(gameType, id)
+	i.pool = s.pool(gameType)
+	return i
+}
+
+// newGameID picks an id that no game has, whether live, archived or still
+// being made, and holds it until the game is in the core, or has failed, so
+// that nothing else writes to its save meanwhile. This is for the core.
+func (s *server) newGameID() string {
+	for {
+		id := RandomString(6)
+		_, live := s.games[id]
+		_, archived := s.archive[id]
+		if live || archived || s.reserved[id] {
+			continue
+		}
+		if s.reserved == nil {
+			s.reserved = map[string]bool{}
+		}
+		s.reserved[id] = true
+		return id
+	}
+}
+
+// pool gets the plugin pool for a game type. This is for the core.
+func (s *server) pool(gameType string) *pluginPool {
+	p, ok := s.pools[gameType]
+	if !ok {
+		if s.pools == nil {
+			s.pools = map[string]*pluginPool{}
+		}
+		p = newPluginPool(gameType, s.pluginProcs)
+		s.pools[gameType] = p
+	}
+	return p
 }
 
 type server struct {
 	// game types
 	gameTypes []string
+	// what the game types say about themselves
+	types map[string]game.Description
+	// files from the game types, for the web gateway, which reads them without
+	// going through the core, so they're changed under the lock
+	assetsLock sync.RWMutex
+	assets     map[string]map[string]*asset
 	// game instances
 	games map[string]*instance
+	// ids of games being made, which aren't in games yet
+	reserved map[string]bool
 	// control channel
 	coreCh chan interface{}
+	// token for admin API
+	adminToken string
+	// API tokens, mapped to user names
+	apiTokens map[string]string
+	// user accounts, if enabled
+	accounts *accountStore
+	// server-wide webhooks
+	webhooks []webhook
+	// for sending to webhooks
+	hookSender *hookSender
+	// where webhooks can be sent
+	hookPolicy hookPolicy
+	// results of finished games, for ratings
+	results *resultStore
+	// summaries of archived games
+	archive map[string]ArchiveSummary
+	// how long won games are kept before archiving
+	completeAfter time.Duration
+	// how long archived games are kept, or forever if zero
+	archiveRetention time.Duration
+	// how long before silent clients are dropped
+	idleTimeout time.Duration
+	// TLS for the gateways, if any
+	tlsConfig *tls.Config
+	// pacing for bots
+	botDelay time.Duration
+	// most plugin processes per game type, or 0 for one per game
+	pluginProcs int
+	// plugin processes, by game type
+	pools map[string]*pluginPool
 }
 
 func (s *server) Run(ctx context.Context) error {
@@ -60,6 +248,13 @@ func (s *server) Run(ctx context.Context) error {
 		close(s.coreCh)
 	}()
 
+	types, assets := describeTypes(ctx, s.gameTypes)
+	s.types = types
+	s.assetsLock.Lock()
+	s.assets = assets
+	s.assetsLock.Unlock()
+	s.hookSender = newHookSender(ctx, s.hookPolicy)
+
 	for _, instance := range s.games {
 		// XXX - starts all games, and does it serially
 		err := instance.StartLoad(ctx)
@@ -69,11 +264,31 @@ func (s *server) Run(ctx context.Context) error {
 			if err != nil {
 				log.Err(err).Msgf("instance shutdown failed: %s", instance.id)
 			}
+			continue
+		}
+		s.noteState(instance)
+		if instance.state != nil && instance.state.Status == string(game.StatusWon) {
+			s.scheduleComplete(instance)
 		}
+		s.startBots(instance)
 	}
 
+	go func() {
+		tick := time.NewTicker(time.Hour)
+		defer tick.Stop()
+		for {
+			s.coreCh <- purgeArchiveMsg{}
+			select {
+			case <-tick.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	_ = runTcpGateway(ctx, s, "0.0.0.0:1234")
 	_ = runWebGateway(ctx, s, "0.0.0.0:1235")
+	_ = runGRPCGateway(ctx, s, "0.0.0.0:1236")
 
 	// this is the server's main loop
 	for in := range s.coreCh {
@@ -81,14 +296,32 @@ func (s *server) Run(ctx context.Context) error {
 		var news []game.Change
 
 		switch msg := in.(type) {
+		case listTypesMsg:
+			s.doListTypes(msg)
 		case listGamesMsg:
 			s.doListGames(msg)
+		case myGamesMsg:
+			s.doMyGames(msg)
+		case linkSeatMsg:
+			s.doLinkSeat(msg)
 		case createGameMsg:
 			s.doCreateGame(msg)
 		case afterCreate:
+			delete(s.reserved, msg.game.id)
 			s.games[msg.game.id] = msg.game
 			msg.in.Rep <- msg.out
+			s.noteState(msg.game)
+			s.fireHooks(msg.game, EventGameCreated, "")
+			s.startBots(msg.game)
 			g = msg.game
+		case releaseIDMsg:
+			delete(s.reserved, msg.Game)
+		case forkGameMsg:
+			s.doForkGame(msg)
+		case exportGameMsg:
+			s.doExportGame(msg)
+		case importGameMsg:
+			s.doImportGame(msg)
 		case queryGameMsg:
 			s.doQueryGame(msg)
 		case deleteGameMsg:
@@ -97,62 +330,157 @@ func (s *server) Run(ctx context.Context) error {
 			g, news = s.doConnect(msg)
 		case disconnectMsg:
 			g, news = s.doDisconnect(msg)
+		case watchMsg:
+			s.doWatch(msg)
+		case unwatchMsg:
+			s.doUnwatch(msg)
 		case textFromUser:
 			g, news = s.doTextMessage(msg)
 		case requestFromUser:
 			s.doUserRequest(msg)
+		case restRequestMsg:
+			s.doRestRequest(msg)
+		case playerStateMsg:
+			s.doPlayerState(msg)
 		case afterRequest:
-			g, news = msg.game, msg.news
+			g, news = s.afterUserRequest(msg)
+		case listHooksMsg:
+			s.doListHooks(msg)
+		case addHookMsg:
+			s.doAddHook(msg)
+		case removeHookMsg:
+			s.doRemoveHook(msg)
+		case leaderboardMsg:
+			s.doLeaderboard(msg)
+		case historyMsg:
+			s.doHistory(msg)
+		case listArchiveMsg:
+			s.doListArchive(msg)
+		case getArchivedMsg:
+			s.doGetArchived(msg)
+		case completeGameMsg:
+			s.doCompleteGame(msg)
+		case purgeArchiveMsg:
+			s.doPurgeArchive(msg)
+		case adminListMsg:
+			s.doAdminList(msg)
+		case adminRestartMsg:
+			s.doAdminRestart(msg)
+		case afterRestart:
+			g, news = s.afterAdminRestart(msg)
+		case adminUpgradeMsg:
+			s.doAdminUpgrade(msg)
+		case afterUpgrade:
+			s.afterAdminUpgrade(msg)
+		case adminSaveMsg:
+			s.doAdminSave(msg)
+		case adminEndMsg:
+			s.doAdminEnd(msg)
+		case adminKickMsg:
+			s.doAdminKick(msg)
 		default:
 			log.Warn().Msgf("nonsense in core: %#v", in)
 		}
 
 		if g != nil && len(news) > 0 {
-			gState := g.state
-
-			var players []game.Presence
-			for _, pState := range g.state.Players {
-				_, here := g.clients[pState.Name]
-				players = append(players, game.Presence{
-					Name:      pState.Name,
-					Connected: here,
-				})
+			err := appendNews(g.gameType, g.id, news)
+			if err != nil {
+				g.log.Err(err).Msg("cannot log news")
 			}
+			s.sendUpdates(g, news)
+			s.noticeChanges(g)
+		}
+	}
 
-			for _, pState := range g.state.Players {
-				client, here := g.clients[pState.Name]
-				if !here {
-					g.log.Info().Msgf("client not connected: %s", pState.Name)
-					continue
-				}
+	return nil
+}
 
-				update := game.GameUpdate{
-					News:       news,
-					Status:     game.GameStatus(gState.Status),
-					Playing:    gState.Playing,
-					Winner:     gState.Winner,
-					TurnNumber: int(gState.TurnNumber),
-					Players:    players,
-					Global:     json.RawMessage(gState.Global),
-					Private:    pState.Private,
-					Turn:       game.UnwrapTurnState(pState.Turn),
-				}
+// seenState is the state of a game when last checked, to spot changes.
+type seenState struct {
+	status  string
+	playing string
+}
 
-				msg, err := comms.Encode("update", update)
-				if err != nil {
-					g.log.Error().Err(err).Msg("failed to encode update")
-					panic("encode update error")
-				}
+// noteState records the state of a game, without acting on it, e.g. after
+// loading.
+func (s *server) noteState(g *instance) {
+	if g.state != nil {
+		g.seen = seenState{g.state.Status, g.state.Playing}
+	}
+}
 
-				err = client.trySend(msg)
-				if err != nil {
-					g.log.Info().Err(err).Msgf("client lagging: %s", pState.Name)
-				}
-			}
+// noticeChanges acts on whatever has changed in a game since last time, by
+// firing webhooks, recording results, and getting won games archived.
+func (s *server) noticeChanges(g *instance) {
+	if g.state == nil {
+		return
+	}
+	was := g.seen
+	s.noteState(g)
+	now := g.seen
+
+	if now.status != was.status {
+		switch game.GameStatus(now.status) {
+		case game.StatusInProgress:
+			s.fireHooks(g, EventGameStarted, "")
+		case game.StatusWon:
+			s.recordResult(g)
+			s.fireHooks(g, EventGameWon, g.state.Winner)
+			s.scheduleComplete(g)
 		}
 	}
+	if now.status == string(game.StatusInProgress) && now.playing != "" && now.playing != was.playing {
+		s.fireHooks(g, EventTurn, now.playing)
+	}
+}
 
-	return nil
+// sendUpdates sends news, and the current state, to every player.
+func (s *server) sendUpdates(g *instance, news []game.Change) {
+	for _, pState := range g.state.Players {
+		update := playerView(g, pState, news)
+
+		// sent even if not connected, so that the session can replay it
+		err := g.sendUpdate(pState.Name, update)
+		if err == errNotConnected {
+			g.log.Info().Msgf("client not connected: %s", pState.Name)
+		} else if err != nil {
+			g.log.Info().Err(err).Msgf("client lagging: %s", pState.Name)
+		}
+	}
+}
+
+// playerView is the state of a game as one player sees it, with some news.
+func playerView(g *instance, pState *game.RPlayerState, news []game.Change) game.GameUpdate {
+	gState := g.state
+
+	var players []game.Presence
+	for _, p := range gState.Players {
+		_, here := g.clients[p.Name]
+		players = append(players, game.Presence{
+			Name:      p.Name,
+			Connected: here,
+		})
+	}
+
+	return game.GameUpdate{
+		News:       news,
+		Status:     game.GameStatus(gState.Status),
+		Playing:    gState.Playing,
+		Winner:     gState.Winner,
+		TurnNumber: int(gState.TurnNumber),
+		Players:    players,
+		Global:     json.RawMessage(gState.Global),
+		Private:    pState.Private,
+		Turn:       game.UnwrapTurnState(pState.Turn),
+	}
+}
+
+func (s *server) doListTypes(in listTypesMsg) {
+	list := []game.Description{}
+	for _, gt := range s.gameTypes {
+		list = append(list, s.types[gt])
+	}
+	in.Rep <- list
 }
 
 func (s *server) doListGames(in listGamesMsg) {
@@ -166,8 +494,41 @@ func (s *server) doListGames(in listGamesMsg) {
 func (s *server) doCreateGame(in createGameMsg) {
 	ctx := context.TODO()
 
-	id := RandomString(6)
-	i := newInstance(in.Req.Type, id)
+	desc, ok := s.types[in.Req.Type]
+	if !ok {
+		in.Rep <- MakeGameOutput{Err: comms.WrapError(fmt.Errorf("unknown game type: %s", in.Req.Type))}
+		return
+	}
+
+	err := checkMakeGame(desc, in.Req)
+	if err == nil {
+		err = checkBots(desc, in.Req.Players)
+	}
+	if err == nil {
+		err = s.checkAccounts(in.Req.Players)
+	}
+	if err != nil {
+		in.Rep <- MakeGameOutput{Err: comms.WrapError(err)}
+		return
+	}
+
+	id := s.newGameID()
+	i := s.newInstance(in.Req.Type, id)
+	i.meta = gameMeta{Owner: in.Owner, Created: time.Now()}
+	for _, pl := range in.Req.Players {
+		if pl.Bot != "" {
+			if i.meta.Bots == nil {
+				i.meta.Bots = map[string]string{}
+			}
+			i.meta.Bots[pl.Name] = pl.Bot
+		}
+		if pl.Account != "" {
+			if i.meta.Accounts == nil {
+				i.meta.Accounts = map[string]string{}
+			}
+			i.meta.Accounts[pl.Name] = pl.Account
+		}
+	}
 
 	go func() {
 		err := i.StartInit(ctx, in.Req)
@@ -178,15 +539,25 @@ func (s *server) doCreateGame(in createGameMsg) {
 			if err != nil {
 				log.Err(err).Msgf("instance shutdown failed: %s", i.id)
 			}
+			s.coreCh <- releaseIDMsg{i.id}
 			return
 		}
 
+		err = saveMeta(i.gameType, i.id, i.meta)
+		if err != nil {
+			log.Err(err).Msgf("instance meta save failed: %s", i.id)
+		}
+
 		players := map[string]string{}
 		for _, pl := range in.Req.Players {
+			if pl.Bot != "" {
+				// nobody else can take a bot's seat
+				continue
+			}
 			players[pl.Name] = encodeConnectString(id, pl.Name)
 		}
 
-		out := MakeGameOutput{Type: in.Req.Type, ID: id, Players: players}
+		out := MakeGameOutput{Type: in.Req.Type, ID: id, Owner: in.Owner, Players: players}
 
 		s.coreCh <- afterCreate{in, out, i}
 	}()
@@ -210,15 +581,27 @@ func (s *server) doDeleteGame(in deleteGameMsg) {
 		return
 	}
 
+	if !in.User.CanDelete(game.meta.Owner) {
+		in.Rep <- errNotAllowed
+		return
+	}
+
 	err := game.Destroy()
 	if err != nil {
 		in.Rep <- err
 		return
 	}
 
-	for _, client := range game.clients {
-		close(client.downCh)
+	err = removeMeta(game.gameType, game.id)
+	if err != nil {
+		game.log.Err(err).Msg("meta delete failed")
 	}
+	os.Remove(newsFileName(game.gameType, game.id))
+
+	game.dropClients()
+
+	s.fireHooks(game, EventGameDeleted, "")
+	s.dropHooks(game)
 
 	delete(s.games, in.Name)
 
@@ -228,12 +611,44 @@ func (s *server) doDeleteGame(in deleteGameMsg) {
 func (s *server) doConnect(in connectMsg) (*instance, []game.Change) {
 	instance, ok := s.games[in.GameId]
 	if !ok {
-		in.Rep <- errors.New("game not found")
+		in.Rep <- connectResult{Err: errors.New("game not found")}
 		return nil, nil
 	}
 
+	if _, isBot := instance.meta.Bots[in.PlayerId]; isBot != in.Bot {
+		// connect codes can be made up, so seats are checked here
+		in.Rep <- connectResult{Err: errNotAllowed}
+		return nil, nil
+	}
+
+	if old, ok := instance.clients[in.PlayerId]; ok {
+		// only one connection per player, the old one is dropped
+		close(old.downCh)
+	}
 	instance.clients[in.PlayerId] = &in.Client
-	in.Rep <- nil
+
+	// try to resume the session, otherwise start a new one. a new session
+	// gets the full state from the update that goes out because of this.
+	resumed := false
+	sess, ok := instance.sessions[in.PlayerId]
+	if ok && in.Req.Resume != "" && in.Req.Resume == sess.token && in.Req.Patches == sess.patches {
+		missed, ok := sess.since(in.Req.LastSeq)
+		if ok {
+			for _, msg := range missed {
+				err := in.Client.trySend(msg)
+				if err != nil {
+					instance.log.Info().Err(err).Msgf("client lagging: %s", in.PlayerId)
+				}
+			}
+			resumed = true
+		}
+	}
+	if !resumed {
+		sess = newSession(in.Req.Patches)
+		instance.sessions[in.PlayerId] = sess
+	}
+
+	in.Rep <- connectResult{Token: sess.token, Resumed: resumed}
 
 	return instance, []game.Change{{
 		Who:  in.PlayerId,
@@ -247,6 +662,12 @@ func (s *server) doDisconnect(in disconnectMsg) (*instance, []game.Change) {
 		return nil, nil
 	}
 
+	client, ok := g.clients[in.Name]
+	if !ok || client.downCh != in.Client.downCh {
+		// already replaced by a newer connection
+		return nil, nil
+	}
+
 	g.log.Info().Msgf("client gone: %s", in.Name)
 
 	delete(g.clients, in.Name)
@@ -256,6 +677,80 @@ func (s *server) doDisconnect(in disconnectMsg) (*instance, []game.Change) {
 	}}
 }
 
+// doWatch attaches a stream to a player's session, without it taking the seat.
+// It can resume from the session, but otherwise just starts with the state
+// as it is now, and as it doesn't change the game, nobody else gets anything.
+func (s *server) doWatch(in watchMsg) {
+	g, ok := s.games[in.GameId]
+	if !ok {
+		in.Rep <- connectResult{Err: errGameNotFound}
+		return
+	}
+	var pState *game.RPlayerState
+	if g.state != nil {
+		for _, pl := range g.state.Players {
+			if pl.Name == in.PlayerId {
+				pState = pl
+			}
+		}
+	}
+	if pState == nil {
+		in.Rep <- connectResult{Err: errNoSeat}
+		return
+	}
+	if g.meta.Bots[in.PlayerId] != "" {
+		in.Rep <- connectResult{Err: errNotAllowed}
+		return
+	}
+
+	sess, ok := g.sessions[in.PlayerId]
+	if !ok {
+		// nobody has connected, but the stream still needs numbering
+		sess = newSession(false)
+		g.sessions[in.PlayerId] = sess
+	}
+
+	// patches can't be replayed, as watchers are sent whole updates instead
+	resumed := false
+	if in.Req.Resume != "" && in.Req.Resume == sess.token && !sess.patches {
+		missed, ok := sess.since(in.Req.LastSeq)
+		if ok {
+			for _, msg := range missed {
+				err := in.Client.trySend(msg)
+				if err != nil {
+					g.log.Info().Err(err).Msgf("watcher lagging: %s", in.PlayerId)
+				}
+			}
+			resumed = true
+		}
+	}
+	if !resumed {
+		in.Client.trySend(toSend{"update", playerView(g, pState, nil)})
+	}
+
+	g.watchers[in.PlayerId] = append(g.watchers[in.PlayerId], &in.Client)
+
+	in.Rep <- connectResult{Token: sess.token, Resumed: resumed}
+}
+
+func (s *server) doUnwatch(in unwatchMsg) {
+	g, ok := s.games[in.Game]
+	if !ok {
+		return
+	}
+
+	list := g.watchers[in.Name]
+	for n, w := range list {
+		if w.downCh == in.Client.downCh {
+			g.watchers[in.Name] = append(list[:n], list[n+1:]...)
+			break
+		}
+	}
+	if len(g.watchers[in.Name]) == 0 {
+		delete(g.watchers, in.Name)
+	}
+}
+
 func (s *server) doTextMessage(in textFromUser) (*instance, []game.Change) {
 	g, ok := s.games[in.Game]
 	if !ok {
@@ -266,6 +761,16 @@ func (s *server) doTextMessage(in textFromUser) (*instance, []game.Change) {
 		{Who: in.Who, What: "says " + in.Text},
 	}
 
+	// watchers can't pick chat out of the news, so get it by itself as well
+	for name, list := range g.watchers {
+		for _, w := range list {
+			err := w.trySend(toSend{"chat", chatMessage{in.Who, in.Text}})
+			if err != nil {
+				g.log.Info().Err(err).Msgf("watcher lagging: %s", name)
+			}
+		}
+	}
+
 	return g, news
 }
 
@@ -276,26 +781,77 @@ func (s *server) doUserRequest(in requestFromUser) {
 		return
 	}
 
+	if in.Cmd[0] == "snapshot" {
+		// this is about the session, not the game, so is done here
+		s.doSnapshot(g, in)
+		return
+	}
+
+	h := g.host
 	go func() {
-		res, news := s.doUserRequestSub(g, in)
+		res, news := s.doUserRequestSub(g, h, in)
 
 		msg := responseToUser{ID: in.ID, Body: res}
-		c := g.clients[in.Who]
 
-		err := c.trySend(msg)
-		if err != nil {
-			g.log.Info().Err(err).Msgf("client lagging: %s", in.Who)
+		s.coreCh <- afterRequest{g, in.Who, msg, news, nil}
+	}()
+}
+
+func (s *server) afterUserRequest(in afterRequest) (*instance, []game.Change) {
+	if in.rep != nil {
+		in.rep <- restResult{Res: in.res.Body}
+		return in.game, in.news
+	}
+
+	err := in.game.send(in.who, in.res)
+	if err != nil {
+		in.game.log.Info().Err(err).Msgf("client lagging: %s", in.who)
+	}
+
+	if len(in.news) == 0 && in.game.meta.Bots[in.who] != "" {
+		// a bot waits for an update after a play that works, and there's
+		// only one for everyone if something happened
+		if res, ok := in.res.Body.(game.PlayResultJSON); ok && res.Err == nil {
+			s.sendUpdateTo(in.game, in.who)
 		}
+	}
 
-		s.coreCh <- afterRequest{g, news}
-	}()
+	return in.game, in.news
+}
+
+// sendUpdateTo sends one player an update with no news.
+func (s *server) sendUpdateTo(g *instance, player string) {
+	for _, pState := range g.state.Players {
+		if pState.Name == player {
+			err := g.sendUpdate(player, playerView(g, pState, nil))
+			if err != nil {
+				g.log.Info().Err(err).Msgf("client lagging: %s", player)
+			}
+		}
+	}
+}
+
+// doSnapshot sends a player the whole of the game, so that patches after it
+// are against it, for when a client has lost track.
+func (s *server) doSnapshot(g *instance, in requestFromUser) {
+	if sess, ok := g.sessions[in.Who]; ok {
+		sess.base = nil
+	}
+	s.sendUpdateTo(g, in.Who)
+
+	err := g.send(in.Who, responseToUser{ID: in.ID})
+	if err != nil {
+		g.log.Info().Err(err).Msgf("client lagging: %s", in.Who)
+	}
 }
 
-func (s *server) doUserRequestSub(g *instance, in requestFromUser) (interface{}, []game.Change) {
+// doUserRequestSub does a request, off the core, with the host that the game
+// was in when the request came in.
+func (s *server) doUserRequestSub(g *instance, h *pluginHost, in requestFromUser) (interface{}, []game.Change) {
 	f := in.Cmd
 	switch f[0] {
 	case "start":
-		err := g.Start()
+		err := g.Start(h)
 		if err != nil {
 			return game.StartResultJSON{
 				Err: comms.WrapError(err),
@@ -320,7 +876,7 @@ func (s *server) doUserRequestSub(g *instance, in requestFromUser) (interface{},
 			return game.PlayResultJSON{Err: comms.WrapError(fmt.Errorf("bad body: %w", err))}, nil
 		}
 
-		news, res, err := g.Play(in.Who, gameCommand)
+		news, res, err := g.Play(h, in.Who, gameCommand)
 		if err != nil {
 			return game.PlayResultJSON{Err: comms.WrapError(err)}, nil
 		}
@@ -332,22 +888,43 @@ func (s *server) doUserRequestSub(g *instance, in requestFromUser) (interface{},
 }
 
 func (s *server) broadcast(g *instance, msg comms.Message, skip string) {
-	for n, c := range g.clients {
+	for n := range g.sessions {
 		if n == skip {
 			continue
 		}
-		select {
-		case c.downCh <- msg:
-		default:
-			// client lagging
-			g.log.Info().Msgf("client lagging: %s", n)
+		err := g.send(n, msg)
+		if err != nil && err != errNotConnected {
+			g.log.Info().Err(err).Msgf("client lagging: %s", n)
 		}
 	}
 }
 
-func (s *server) Connect(gameId, playerId string, client clientBundle) error {
-	resCh := make(chan error)
-	s.coreCh <- connectMsg{gameId, playerId, client, resCh}
+// Connect attaches a client to a player in a game, resuming the session if
+// the request allows.
+func (s *server) Connect(gameId, playerId string, req comms.ConnectRequest, client clientBundle) connectResult {
+	resCh := make(chan connectResult)
+	s.coreCh <- connectMsg{gameId, playerId, req, client, resCh, false}
+	return <-resCh
+}
+
+// connectBot is Connect for a bot, into a bot seat.
+func (s *server) connectBot(gameId, playerId string, client clientBundle) connectResult {
+	resCh := make(chan connectResult)
+	s.coreCh <- connectMsg{gameId, playerId, comms.ConnectRequest{}, client, resCh, true}
+	return <-resCh
+}
+
+// Watch attaches a read-only stream to a player's messages, which doesn't
+// take the seat from whoever is playing it.
+func (s *server) Watch(gameId, playerId string, req comms.ConnectRequest, client clientBundle) connectResult {
+	resCh := make(chan connectResult)
+	s.coreCh <- watchMsg{gameId, playerId, req, client, resCh}
+	return <-resCh
+}
+
+func (s *server) ListTypes() []game.Description {
+	resCh := make(chan []game.Description)
+	s.coreCh <- listTypesMsg{resCh}
 	return <-resCh
 }
 
@@ -357,9 +934,21 @@ func (s *server) ListGames() []string {
 	return <-resCh
 }
 
-func (s *server) CreateGame(req MakeGameInput) MakeGameOutput {
+func (s *server) CreateGame(req MakeGameInput, owner string) MakeGameOutput {
 	resCh := make(chan MakeGameOutput)
-	s.coreCh <- createGameMsg{req, resCh}
+	s.coreCh <- createGameMsg{req, owner, resCh}
+	return <-resCh
+}
+
+func (s *server) MyGames(user string) []MyGame {
+	resCh := make(chan []MyGame)
+	s.coreCh <- myGamesMsg{user, resCh}
+	return <-resCh
+}
+
+func (s *server) LinkSeat(gameId, seat, account string, user authUser) error {
+	resCh := make(chan error)
+	s.coreCh <- linkSeatMsg{gameId, seat, account, user, resCh}
 	return <-resCh
 }
 
@@ -369,8 +958,8 @@ func (s *server) QueryGame(name string) interface{} {
 	return <-resCh
 }
 
-func (s *server) DeleteGame(name string) error {
+func (s *server) DeleteGame(name string, user authUser) error {
 	resCh := make(chan error)
-	s.coreCh <- deleteGameMsg{name, resCh}
+	s.coreCh <- deleteGameMsg{name, user, resCh}
 	return <-resCh
 }
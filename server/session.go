@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// sessionBufferSize is how many sent messages are kept for replay.
+const sessionBufferSize = 50
+
+var errNotConnected = errors.New("not connected")
+
+// session is a player's stream of messages from the server. It outlives any
+// one connection, so that messages sent while a client is away can be
+// replayed when it comes back.
+type session struct {
+	// token that a client must show to resume
+	token string
+	// last seq used
+	seq uint32
+	// recently sent messages, oldest first
+	buffer []comms.Message
+
+	// whether the client takes patches
+	patches bool
+	// the last update that got to the client, as JSON without news, and its
+	// seq, for patches
+	base    []byte
+	baseSeq uint32
+}
+
+func newSession(patches bool) *session {
+	return &session{
+		token:   randomToken(16),
+		patches: patches,
+	}
+}
+
+// number gives a message the next seq, and keeps it for replay.
+func (s *session) number(msg comms.Message) comms.Message {
+	s.seq++
+	msg.Seq = s.seq
+
+	if len(s.buffer) >= sessionBufferSize {
+		copy(s.buffer, s.buffer[1:])
+		s.buffer = s.buffer[:len(s.buffer)-1]
+	}
+	s.buffer = append(s.buffer, msg)
+
+	return msg
+}
+
+// since finds all messages after lastSeq. It returns false if any have been
+// dropped from the buffer, or if lastSeq makes no sense.
+func (s *session) since(lastSeq uint32) ([]comms.Message, bool) {
+	if lastSeq > s.seq {
+		return nil, false
+	}
+	if lastSeq == s.seq {
+		return nil, true
+	}
+	if len(s.buffer) == 0 || s.buffer[0].Seq > lastSeq+1 {
+		return nil, false
+	}
+
+	var out []comms.Message
+	for _, msg := range s.buffer {
+		if msg.Seq > lastSeq {
+			out = append(out, msg)
+		}
+	}
+	return out, true
+}
+
+// encodeUpdate makes the message for an update, which is a patch if the
+// client takes them and there is an update to patch. It returns the update as
+// JSON without news, for the next patch to be against, once it's been sent.
+func (s *session) encodeUpdate(update game.GameUpdate) (comms.Message, []byte, error) {
+	news := update.News
+	update.News = nil
+	doc, err := json.Marshal(update)
+	if err != nil {
+		return comms.Message{}, nil, err
+	}
+
+	if !s.patches || s.base == nil {
+		update.News = news
+		msg, err := comms.Encode("update", update)
+		return msg, doc, err
+	}
+
+	patch, err := comms.MergePatch(s.base, doc)
+	if err != nil {
+		return comms.Message{}, nil, err
+	}
+	msg, err := comms.Encode("patch", game.GameUpdatePatch{
+		Base:  s.baseSeq,
+		News:  news,
+		Patch: patch,
+	})
+	return msg, doc, err
+}
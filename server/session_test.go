@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/undeconstructed/gogogo/comms"
+	"github.com/undeconstructed/gogogo/game"
+)
+
+func TestSession_since(t *testing.T) {
+	s := newSession(false)
+	for i := 0; i < 10; i++ {
+		msg, _ := comms.Encode("update", i)
+		s.number(msg)
+	}
+
+	msgs, ok := s.since(7)
+	if !ok {
+		t.Fatalf("cannot replay")
+	}
+	if len(msgs) != 3 || msgs[0].Seq != 8 || msgs[2].Seq != 10 {
+		t.Errorf("bad replay: %v", msgs)
+	}
+
+	msgs, ok = s.since(10)
+	if !ok || len(msgs) != 0 {
+		t.Errorf("bad replay when up to date: %v", msgs)
+	}
+
+	_, ok = s.since(11)
+	if ok {
+		t.Errorf("replayed from the future")
+	}
+}
+
+func TestSession_overflow(t *testing.T) {
+	s := newSession(false)
+	for i := 0; i < sessionBufferSize+10; i++ {
+		msg, _ := comms.Encode("update", i)
+		s.number(msg)
+	}
+
+	if len(s.buffer) != sessionBufferSize {
+		t.Errorf("buffer not bounded: %d", len(s.buffer))
+	}
+
+	_, ok := s.since(5)
+	if ok {
+		t.Errorf("replayed dropped messages")
+	}
+
+	msgs, ok := s.since(10)
+	if !ok || len(msgs) != sessionBufferSize {
+		t.Errorf("bad replay from start of buffer: %d", len(msgs))
+	}
+}
+
+func TestSession_encodeUpdate(t *testing.T) {
+	g := newInstance("go", "g1")
+	s := newSession(true)
+	g.sessions["phil"] = s
+	g.clients["phil"] = &clientBundle{make(chan interface{}, 10)}
+
+	update := game.GameUpdate{
+		News:    []game.Change{{Who: "phil", What: "moves"}},
+		Status:  game.StatusInProgress,
+		Playing: "phil",
+		Global:  json.RawMessage(`{"players":{"phil":{"square":1},"robo":{"square":0}}}`),
+	}
+	g.sendUpdate("phil", update)
+
+	// first a whole update
+	if msg := s.buffer[0]; msg.Head != "update" {
+		t.Fatalf("expected update, got %s", msg.Head)
+	}
+
+	update.Playing = "robo"
+	update.News = []game.Change{{Who: "phil", What: "goes to sleep"}}
+	update.Global = json.RawMessage(`{"players":{"phil":{"square":1},"robo":{"square":3}}}`)
+	g.sendUpdate("phil", update)
+
+	// then patches to it
+	msg := s.buffer[1]
+	if msg.Head != "patch" {
+		t.Fatalf("expected patch, got %s", msg.Head)
+	}
+	patch := game.GameUpdatePatch{}
+	comms.Decode(msg, &patch)
+	if patch.Base != 1 || len(patch.News) != 1 || patch.News[0].What != "goes to sleep" {
+		t.Errorf("bad patch: %v", patch)
+	}
+	if string(patch.Patch) != `{"global":{"players":{"robo":{"square":3}}},"playing":"robo"}` {
+		t.Errorf("bad patch: %s", patch.Patch)
+	}
+	if s.baseSeq != 2 {
+		t.Errorf("bad base seq: %d", s.baseSeq)
+	}
+
+	// but not for clients that can't take them
+	s = newSession(false)
+	g.sessions["phil"] = s
+	g.sendUpdate("phil", update)
+	g.sendUpdate("phil", update)
+	if s.buffer[1].Head != "update" {
+		t.Errorf("expected update, got %s", s.buffer[1].Head)
+	}
+}
+
+func TestSession_failedSend(t *testing.T) {
+	g := newInstance("go", "g1")
+	s := newSession(true)
+	g.sessions["phil"] = s
+	client := &clientBundle{make(chan interface{}, 1)}
+	g.clients["phil"] = client
+
+	update := game.GameUpdate{Status: game.StatusInProgress, Playing: "phil"}
+	send := func(playing string) (comms.Head, error) {
+		update.Playing = playing
+		err := g.sendUpdate("phil", update)
+		return s.buffer[len(s.buffer)-1].Head, err
+	}
+
+	if head, err := send("phil"); head != "update" || err != nil {
+		t.Fatalf("expected update sent, got %s %v", head, err)
+	}
+	// the queue is full, so this is lost, until the client resumes
+	if _, err := send("robo"); err == nil {
+		t.Fatalf("expected send to fail")
+	}
+	<-client.downCh
+
+	// so the client can't take a patch, whichever update it has
+	if head, err := send("phil"); head != "update" || err != nil {
+		t.Errorf("expected whole update after failure, got %s %v", head, err)
+	}
+	<-client.downCh
+	if head, err := send("robo"); head != "patch" || err != nil || s.baseSeq != 4 {
+		t.Errorf("expected patch after success, got %s %v %d", head, err, s.baseSeq)
+	}
+}
+
+// BenchmarkUpdates sends the updates of a made up 6 player game, whole or as
+// patches, and reports the bytes sent per update.
+func BenchmarkUpdates(b *testing.B) {
+	states := fakeStates(6, 300)
+
+	for _, patches := range []bool{false, true} {
+		name := "full"
+		if patches {
+			name = "patch"
+		}
+		b.Run(name, func(b *testing.B) {
+			sent, updates := 0, 0
+			for n := 0; n < b.N; n++ {
+				g := newInstance("go", "bench")
+				for _, state := range states {
+					g.state = state
+					for _, pState := range state.Players {
+						sess, ok := g.sessions[pState.Name]
+						if !ok {
+							sess = newSession(patches)
+							g.sessions[pState.Name] = sess
+							g.clients[pState.Name] = &clientBundle{make(chan interface{}, 1)}
+						}
+						g.sendUpdate(pState.Name, playerView(g, pState, nil))
+						<-g.clients[pState.Name].downCh
+						sent += len(sess.buffer[len(sess.buffer)-1].Data)
+						updates++
+					}
+				}
+			}
+			b.ReportMetric(float64(sent)/float64(updates), "bytes/update")
+		})
+	}
+}
+
+// fakeStates makes the states of a game something like go, where each command
+// changes a little of what the player has, and the turn moves on every few.
+func fakeStates(players int, commands int) []*game.RGameState {
+	rnd := rand.New(rand.NewSource(1))
+
+	type fakePlayer struct {
+		Colour    string         `json:"colour"`
+		Square    int            `json:"square"`
+		Dot       string         `json:"dot"`
+		Money     map[string]int `json:"money"`
+		Souvenirs []string       `json:"souvenirs"`
+		Lucks     []int          `json:"lucks"`
+	}
+	global := struct {
+		Players map[string]*fakePlayer `json:"players"`
+	}{map[string]*fakePlayer{}}
+
+	var names []string
+	for i := 0; i < players; i++ {
+		name := fmt.Sprintf("p%d", i)
+		names = append(names, name)
+		global.Players[name] = &fakePlayer{
+			Colour: name,
+			Dot:    "418,193",
+			Money:  map[string]int{"st": 400},
+		}
+	}
+
+	var states []*game.RGameState
+	playing := 0
+	for n := 0; n < commands; n++ {
+		name := names[playing]
+		pl := global.Players[name]
+		switch rnd.Intn(4) {
+		case 0:
+			pl.Square = (pl.Square + rnd.Intn(6) + 1) % 40
+		case 1:
+			pl.Dot = fmt.Sprintf("%d,%d", rnd.Intn(1000), rnd.Intn(600))
+		case 2:
+			pl.Money["st"] -= rnd.Intn(50)
+			pl.Money[fmt.Sprintf("c%d", rnd.Intn(10))] += rnd.Intn(100)
+		case 3:
+			pl.Lucks = append(pl.Lucks, rnd.Intn(30))
+		}
+		if rnd.Intn(20) == 0 {
+			pl.Souvenirs = append(pl.Souvenirs, fmt.Sprintf("place%d", rnd.Intn(20)))
+		}
+		if rnd.Intn(4) == 0 {
+			playing = (playing + 1) % players
+		}
+
+		state := &game.RGameState{
+			Status:     string(game.StatusInProgress),
+			Playing:    names[playing],
+			TurnNumber: int32(n/4 + 1),
+		}
+		state.Global, _ = json.Marshal(global)
+		for _, name := range names {
+			pState := &game.RPlayerState{Name: name}
+			if name == state.Playing {
+				pState.Turn = &game.RTurnState{
+					Number: state.TurnNumber,
+					Can:    []string{"dicemove", "stop", "useluck:*"},
+				}
+			}
+			state.Players = append(state.Players, pState)
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func TestWatch(t *testing.T) {
+	s := &server{games: map[string]*instance{}}
+
+	g := newInstance("go", "g1")
+	g.meta = gameMeta{Bots: map[string]string{"robo": "random"}}
+	g.state = &game.RGameState{
+		Status:  string(game.StatusInProgress),
+		Players: []*game.RPlayerState{{Name: "phil"}, {Name: "anna"}, {Name: "robo"}},
+	}
+	s.games[g.id] = g
+
+	// phil is playing, and takes patches
+	client := clientBundle{make(chan interface{}, 10)}
+	g.clients["phil"] = &client
+	g.sessions["phil"] = newSession(true)
+
+	watch := func(player string, req comms.ConnectRequest) (clientBundle, connectResult) {
+		w := clientBundle{make(chan interface{}, 10)}
+		rep := make(chan connectResult, 1)
+		s.doWatch(watchMsg{"g1", player, req, w, rep})
+		return w, <-rep
+	}
+
+	w, res := watch("phil", comms.ConnectRequest{})
+	if res.Err != nil || res.Token != g.sessions["phil"].token {
+		t.Fatalf("expected to watch the session, got %v", res.Err)
+	}
+	if g.clients["phil"] != &client {
+		t.Errorf("watching took the seat")
+	}
+	if msg, _ := encodeDown(<-w.downCh); msg.Type() != "update" || msg.Seq != 0 {
+		t.Errorf("expected the state now, got %s %d", msg.Head, msg.Seq)
+	}
+
+	// the player gets a patch, the watcher the whole update, both numbered
+	view := playerView(g, g.state.Players[0], nil)
+	g.sendUpdate("phil", view)
+	view.TurnNumber = 1
+	g.sendUpdate("phil", view)
+	<-client.downCh
+	if msg, _ := encodeDown(<-client.downCh); msg.Type() != "patch" {
+		t.Errorf("expected patch for the player, got %s", msg.Head)
+	}
+	<-w.downCh
+	if msg, _ := encodeDown(<-w.downCh); msg.Type() != "update" || msg.Seq != 2 {
+		t.Errorf("expected whole update for the watcher, got %s %d", msg.Head, msg.Seq)
+	}
+
+	s.doUnwatch(unwatchMsg{"g1", "phil", w})
+	if len(g.watchers["phil"]) != 0 {
+		t.Errorf("watcher not removed")
+	}
+
+	// a seat nobody has connected to gets a session for the stream
+	if _, res := watch("anna", comms.ConnectRequest{}); res.Err != nil || g.sessions["anna"] == nil {
+		t.Errorf("expected new session, got %v", res.Err)
+	}
+
+	if _, res := watch("robo", comms.ConnectRequest{}); res.Err != errNotAllowed {
+		t.Errorf("expected not allowed for bot, got %v", res.Err)
+	}
+	if _, res := watch("bob", comms.ConnectRequest{}); res.Err != errNoSeat {
+		t.Errorf("expected no seat, got %v", res.Err)
+	}
+
+	// ending the game drops watchers too
+	w, _ = watch("phil", comms.ConnectRequest{})
+	g.dropClients()
+	for range w.downCh {
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path"
+	"testing"
+)
+
+// tlsRoundTrip serves TLS with a config, and checks that a client that trusts
+// the cert in certFile can talk to it.
+func tlsRoundTrip(t *testing.T, config *tls.Config, certFile string) {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 4)
+				n, _ := conn.Read(buf)
+				conn.Write(buf[:n])
+			}()
+		}
+	}()
+
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(certPEM) {
+		t.Fatal("bad cert file")
+	}
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("ping"))
+	buf := make([]byte, 4)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "ping" {
+		t.Errorf("bad echo: %q %v", buf[:n], err)
+	}
+
+	// untrusting clients are refused
+	_, err = tls.Dial("tcp", ln.Addr().String(), &tls.Config{ServerName: "localhost"})
+	if err == nil {
+		t.Errorf("expected unknown authority")
+	}
+}
+
+func TestDevTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile := path.Join(dir, "dev-cert.pem")
+
+	config, err := devTLSConfig(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("bad min version: %x", config.MinVersion)
+	}
+
+	tlsRoundTrip(t, config, certFile)
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile := path.Join(dir, "cert.pem")
+	keyFile := path.Join(dir, "key.pem")
+
+	// a dev cert will do, with its key written out too
+	dev, err := devTLSConfig(certFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(dev.Certificates[0].PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	config, err := loadTLSConfig(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsRoundTrip(t, config, certFile)
+
+	if _, err := loadTLSConfig(certFile, path.Join(dir, "nope.pem")); err == nil {
+		t.Errorf("expected error for missing key")
+	}
+	if _, err := loadTLSConfig(keyFile, keyFile); err == nil {
+		t.Errorf("expected error for bad cert")
+	}
+}
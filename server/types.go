@@ -3,7 +3,9 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
+	"github.com/undeconstructed/gogogo/comms"
 	"github.com/undeconstructed/gogogo/game"
 )
 
@@ -16,27 +18,174 @@ type MakeGameInput struct {
 type MakePlayerInput struct {
 	Name    string          `json:"name"`
 	Options json.RawMessage `json:"options"`
+	// Bot is the kind of bot to play this seat, if not a human
+	Bot string `json:"bot,omitempty"`
+	// Account is the user account to link the seat to, if any
+	Account string `json:"account,omitempty"`
 }
 
 type MakeGameOutput struct {
 	Type    string            `json:"type"`
 	ID      string            `json:"id"`
+	Owner   string            `json:"owner"`
 	Players map[string]string `json:"players"`
 	Err     error             `json:"error"`
 }
 
+// MyGame is a game that a user account has a seat in.
+type MyGame struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Seat     string          `json:"seat"`
+	Code     string          `json:"code"`
+	Status   game.GameStatus `json:"status"`
+	Playing  string          `json:"playing"`
+	YourTurn bool            `json:"yourTurn"`
+}
+
+// InstanceInfo is the admin view of a game instance.
+type InstanceInfo struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Owner   string          `json:"owner"`
+	Status  game.GameStatus `json:"status"`
+	PID     int             `json:"pid"`
+	Bind    string          `json:"bind"`
+	Health  string          `json:"health"`
+	Clients []string        `json:"clients"`
+}
+
 type toSend struct {
 	mtype string
 	data  interface{}
 }
 
+type listTypesMsg struct {
+	Rep chan []game.Description
+}
+
 type listGamesMsg struct {
 	Rep chan []string
 }
 
+type myGamesMsg struct {
+	User string
+	Rep  chan []MyGame
+}
+
+type linkSeatMsg struct {
+	Game    string
+	Seat    string
+	Account string
+	User    authUser
+	Rep     chan error
+}
+
+type listHooksMsg struct {
+	Game string
+	User authUser
+	Rep  chan listHooksResult
+}
+
+type listHooksResult struct {
+	Hooks []webhook
+	Err   error
+}
+
+type addHookMsg struct {
+	Game string
+	User authUser
+	Hook WebhookInput
+	Rep  chan addHookResult
+}
+
+type addHookResult struct {
+	Hook webhook
+	Err  error
+}
+
+type removeHookMsg struct {
+	Game string
+	User authUser
+	ID   string
+	Rep  chan error
+}
+
+type leaderboardMsg struct {
+	Type  string
+	Since time.Time
+	Rep   chan []Rating
+}
+
+type historyMsg struct {
+	Type   string
+	Player string
+	Since  time.Time
+	Rep    chan []HistoryEntry
+}
+
+type listArchiveMsg struct {
+	Type string
+	Rep  chan []ArchiveSummary
+}
+
+type getArchivedMsg struct {
+	Game string
+	Rep  chan getArchivedResult
+}
+
+type getArchivedResult struct {
+	Game *ArchivedGame
+	Err  error
+}
+
+// completeGameMsg is sent a while after a game is won, to archive it.
+type completeGameMsg struct {
+	Game string
+}
+
+// purgeArchiveMsg is sent now and then, to delete old archived games.
+type purgeArchiveMsg struct{}
+
 type createGameMsg struct {
-	Req MakeGameInput
-	Rep chan MakeGameOutput
+	Req   MakeGameInput
+	Owner string
+	Rep   chan MakeGameOutput
+}
+
+// releaseIDMsg gives back the id of a game that couldn't be made.
+type releaseIDMsg struct {
+	Game string
+}
+
+type forkGameMsg struct {
+	Game string
+	User authUser
+	Req  ForkGameInput
+	Rep  chan loadGameResult
+}
+
+type exportGameMsg struct {
+	Game string
+	User authUser
+	Rep  chan exportGameResult
+}
+
+type exportGameResult struct {
+	Bundle *GameBundle
+	Err    error
+}
+
+type importGameMsg struct {
+	Bundle *GameBundle
+	User   authUser
+	Rep    chan loadGameResult
+}
+
+// loadGameResult is the result of starting a game from an existing save.
+type loadGameResult struct {
+	Out MakeGameOutput
+	Err error
 }
 
 type queryGameMsg struct {
@@ -46,19 +195,52 @@ type queryGameMsg struct {
 
 type deleteGameMsg struct {
 	Name string
+	User authUser
 	Rep  chan error
 }
 
 type connectMsg struct {
 	GameId   string
 	PlayerId string
+	Req      comms.ConnectRequest
 	Client   clientBundle
-	Rep      chan error
+	Rep      chan connectResult
+	// Bot is set for a bot taking its own seat, which nobody else can
+	Bot bool
+}
+
+type connectResult struct {
+	Token   string
+	Resumed bool
+	Err     error
 }
 
 type disconnectMsg struct {
-	Game string
-	Name string
+	Game   string
+	Name   string
+	Client clientBundle
+}
+
+// watchMsg is for a read-only stream of a player's messages, which leaves the
+// player's seat, and any connection to it, alone.
+type watchMsg struct {
+	GameId   string
+	PlayerId string
+	Req      comms.ConnectRequest
+	Client   clientBundle
+	Rep      chan connectResult
+}
+
+type unwatchMsg struct {
+	Game   string
+	Name   string
+	Client clientBundle
+}
+
+// chatMessage is chat, as sent to watchers, who otherwise only see it as news.
+type chatMessage struct {
+	Who  string `json:"who"`
+	Text string `json:"text"`
 }
 
 type textFromUser struct {
@@ -75,11 +257,73 @@ type requestFromUser struct {
 	Body interface{}
 }
 
+// restRequestMsg is a request from a player over REST, with no client, so
+// the response comes back on Rep.
+type restRequestMsg struct {
+	Game string
+	Who  string
+	Cmd  []string
+	Body []byte
+	Rep  chan restResult
+}
+
+type restResult struct {
+	Res interface{}
+	Err error
+}
+
+type playerStateMsg struct {
+	Game string
+	Who  string
+	Rep  chan playerStateResult
+}
+
+type playerStateResult struct {
+	Update *game.GameUpdate
+	Err    error
+}
+
 type responseToUser struct {
 	ID   string
 	Body interface{}
 }
 
+type adminListMsg struct {
+	Rep chan []InstanceInfo
+}
+
+type adminRestartMsg struct {
+	Game string
+	Rep  chan error
+}
+
+type adminUpgradeMsg struct {
+	Type string
+	Rep  chan error
+}
+
+type adminSaveMsg struct {
+	Game string
+	Rep  chan adminSaveResult
+}
+
+type adminSaveResult struct {
+	Data []byte
+	Err  error
+}
+
+type adminEndMsg struct {
+	Game    string
+	Archive bool
+	Rep     chan error
+}
+
+type adminKickMsg struct {
+	Game string
+	Name string
+	Rep  chan error
+}
+
 type clientBundle struct {
 	downCh chan interface{}
 }
@@ -101,5 +345,25 @@ type afterCreate struct {
 
 type afterRequest struct {
 	game *instance
+	who  string
+	res  responseToUser
 	news []game.Change
+	// rep is for REST requests, instead of sending to the client
+	rep chan restResult
+}
+
+type afterRestart struct {
+	in    adminRestartMsg
+	game  *instance
+	host  *pluginHost
+	state *game.RGameState
+	err   error
+}
+
+type afterUpgrade struct {
+	in adminUpgradeMsg
+	// what the new binary says it is, if it could be asked
+	desc   *game.Description
+	assets map[string]*asset
+	err    error
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	crand "crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -18,6 +20,18 @@ func RandomString(n int) string {
 	return string(b)
 }
 
+// randomToken makes a secret, which can't be guessed, unlike RandomString,
+// which is only for ids.
+func randomToken(n int) string {
+	b := make([]byte, n)
+	_, err := crand.Read(b)
+	if err != nil {
+		// nothing is safe to use instead
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
 func encodeConnectString(gameId, playerId string) string {
 	s := fmt.Sprintf("%s//%s", gameId, playerId)
 	c := base64.StdEncoding.EncodeToString([]byte(s))
@@ -35,3 +49,28 @@ func decodeConnectString(code string) (gameId, playerId string, err error) {
 	}
 	return ss[0], ss[1], nil
 }
+
+// orEmptyObject is some JSON, or {} if there's none, as options may be left out.
+func orEmptyObject(data json.RawMessage) []byte {
+	if len(data) == 0 {
+		return []byte("{}")
+	}
+	return data
+}
+
+// orNull is some JSON, or null if there's none.
+func orNull(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+func stringListContains(l []string, s string) bool {
+	for _, x := range l {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
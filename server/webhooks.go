@@ -0,0 +1,522 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Webhook events.
+const (
+	EventGameCreated = "game.created"
+	EventGameStarted = "game.started"
+	EventTurn        = "game.turn"
+	EventGameWon     = "game.won"
+	EventGameDeleted = "game.deleted"
+)
+
+var allEvents = []string{EventGameCreated, EventGameStarted, EventTurn, EventGameWon, EventGameDeleted}
+
+var errHookNotFound = errors.New("webhook not found")
+
+// webhooksFile is where server-wide webhooks are kept.
+var webhooksFile = path.Join("run", "webhooks.json")
+
+// webhook is a subscription to events, either for one game or for all.
+type webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+func (h webhook) wants(event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	return stringListContains(h.Events, event)
+}
+
+// WebhookInput is a request to add a webhook. Events can be left empty, to get
+// all of them, and a secret is made if none is given.
+type WebhookInput struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// check checks a webhook, including that its host is one that the policy
+// allows. It may look up the host, so it's not for the core.
+func (in WebhookInput) check(ctx context.Context, policy hookPolicy) error {
+	u, err := url.Parse(in.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("bad url")
+	}
+	for _, e := range in.Events {
+		if !stringListContains(allEvents, e) {
+			return fmt.Errorf("unknown event: %s", e)
+		}
+	}
+	return policy.checkHost(ctx, u.Hostname())
+}
+
+var errHookDenied = errors.New("webhook address not allowed")
+
+// hookPolicy is which addresses webhooks can be sent to. Anything in allow can
+// be, and then nothing in deny can be, nor any loopback, private or link-local
+// address, so that webhooks can't be used to reach into the server's own
+// network, unless it's allowed.
+type hookPolicy struct {
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// privateNets are private address ranges, as in RFC 1918 and RFC 4193.
+var privateNets, _ = parseCIDRs("10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,fc00::/7")
+
+// parseHookPolicy reads lists of CIDRs, separated by commas.
+func parseHookPolicy(allow, deny string) (hookPolicy, error) {
+	var p hookPolicy
+	var err error
+	p.allow, err = parseCIDRs(allow)
+	if err != nil {
+		return p, err
+	}
+	p.deny, err = parseCIDRs(deny)
+	return p, err
+}
+
+func parseCIDRs(list string) ([]*net.IPNet, error) {
+	var out []*net.IPNet
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func (p hookPolicy) allows(ip net.IP) bool {
+	for _, n := range p.allow {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	for _, n := range p.deny {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return !(ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast())
+}
+
+// checkHost checks every address that a host has. Names can point somewhere
+// else later, so addresses are checked again when sending.
+func (p hookPolicy) checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !p.allows(ip) {
+			return errHookDenied
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("cannot find host: %s", host)
+	}
+	for _, a := range addrs {
+		if !p.allows(a.IP) {
+			return errHookDenied
+		}
+	}
+	return nil
+}
+
+// control is for a dialer, to check the address that's actually used.
+func (p hookPolicy) control(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !p.allows(ip) {
+		return errHookDenied
+	}
+	return nil
+}
+
+// WebhookEvent is what is posted to webhooks.
+type WebhookEvent struct {
+	ID     string          `json:"id"`
+	Event  string          `json:"event"`
+	Time   time.Time       `json:"time"`
+	Game   string          `json:"game"`
+	Type   string          `json:"type"`
+	Owner  string          `json:"owner"`
+	Status game.GameStatus `json:"status,omitempty"`
+	// Player is whose turn it is, or who won
+	Player string `json:"player,omitempty"`
+}
+
+func loadWebhooks(file string) ([]webhook, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var hooks []webhook
+	err = json.Unmarshal(data, &hooks)
+	return hooks, err
+}
+
+func saveWebhooks(file string, hooks []webhook) error {
+	err := os.MkdirAll(path.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(hooks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0600)
+}
+
+// fireHooks sends an event to every webhook that wants it.
+func (s *server) fireHooks(g *instance, event, player string) {
+	ev := WebhookEvent{
+		ID:     RandomString(12),
+		Event:  event,
+		Time:   time.Now(),
+		Game:   g.id,
+		Type:   g.gameType,
+		Owner:  g.meta.Owner,
+		Player: player,
+	}
+	if g.state != nil {
+		ev.Status = game.GameStatus(g.state.Status)
+	}
+
+	var hooks []webhook
+	hooks = append(hooks, s.webhooks...)
+	hooks = append(hooks, g.meta.Webhooks...)
+	for _, h := range hooks {
+		if h.wants(event) {
+			s.hookSender.send(h, ev)
+		}
+	}
+}
+
+// dropHooks is for when a game is gone, after its last event. Its webhooks'
+// queues are sent, and then stopped.
+func (s *server) dropHooks(g *instance) {
+	for _, h := range g.meta.Webhooks {
+		s.hookSender.finish(h.ID)
+	}
+}
+
+func (s *server) hooksFor(gameId string, user authUser) (*[]webhook, *instance, error) {
+	if gameId == "" {
+		if !user.Admin {
+			return nil, nil, errNotAllowed
+		}
+		return &s.webhooks, nil, nil
+	}
+	g, ok := s.games[gameId]
+	if !ok {
+		return nil, nil, errGameNotFound
+	}
+	if !user.CanDelete(g.meta.Owner) {
+		return nil, nil, errNotAllowed
+	}
+	return &g.meta.Webhooks, g, nil
+}
+
+func (s *server) saveHooks(g *instance) error {
+	if g == nil {
+		return saveWebhooks(webhooksFile, s.webhooks)
+	}
+	return saveMeta(g.gameType, g.id, g.meta)
+}
+
+func (s *server) doListHooks(in listHooksMsg) {
+	hooks, _, err := s.hooksFor(in.Game, in.User)
+	if err != nil {
+		in.Rep <- listHooksResult{Err: err}
+		return
+	}
+	list := []webhook{}
+	for _, h := range *hooks {
+		// the secret is only shown when the hook is made
+		h.Secret = ""
+		list = append(list, h)
+	}
+	in.Rep <- listHooksResult{Hooks: list}
+}
+
+func (s *server) doAddHook(in addHookMsg) {
+	hooks, g, err := s.hooksFor(in.Game, in.User)
+	if err != nil {
+		in.Rep <- addHookResult{Err: err}
+		return
+	}
+
+	h := webhook{
+		ID:     RandomString(8),
+		URL:    in.Hook.URL,
+		Secret: in.Hook.Secret,
+		Events: in.Hook.Events,
+	}
+	if h.Secret == "" {
+		h.Secret = randomToken(24)
+	}
+	*hooks = append(*hooks, h)
+
+	err = s.saveHooks(g)
+	if err != nil {
+		log.Err(err).Msg("webhook save failed")
+	}
+
+	in.Rep <- addHookResult{Hook: h}
+}
+
+func (s *server) doRemoveHook(in removeHookMsg) {
+	hooks, g, err := s.hooksFor(in.Game, in.User)
+	if err != nil {
+		in.Rep <- err
+		return
+	}
+
+	for n, h := range *hooks {
+		if h.ID == in.ID {
+			*hooks = append((*hooks)[:n], (*hooks)[n+1:]...)
+			s.hookSender.remove(h.ID)
+			err = s.saveHooks(g)
+			if err != nil {
+				log.Err(err).Msg("webhook save failed")
+			}
+			in.Rep <- nil
+			return
+		}
+	}
+
+	in.Rep <- errHookNotFound
+}
+
+func (s *server) ListHooks(gameId string, user authUser) ([]webhook, error) {
+	resCh := make(chan listHooksResult)
+	s.coreCh <- listHooksMsg{gameId, user, resCh}
+	res := <-resCh
+	return res.Hooks, res.Err
+}
+
+func (s *server) AddHook(gameId string, user authUser, hook WebhookInput) (webhook, error) {
+	resCh := make(chan addHookResult)
+	s.coreCh <- addHookMsg{gameId, user, hook, resCh}
+	res := <-resCh
+	return res.Hook, res.Err
+}
+
+func (s *server) RemoveHook(gameId string, user authUser, id string) error {
+	resCh := make(chan error)
+	s.coreCh <- removeHookMsg{gameId, user, id, resCh}
+	return <-resCh
+}
+
+// hookQueueSize is how many events can wait for a slow webhook
+const hookQueueSize = 100
+
+type hookDelivery struct {
+	hook webhook
+	ev   WebhookEvent
+	body []byte
+}
+
+// hookQueue is the events waiting for one webhook, and the goroutine sending
+// them, which stops when the queue is closed and empty, or at once when the
+// context is cancelled.
+type hookQueue struct {
+	ch     chan hookDelivery
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// hookSender posts events to webhooks, off the core. Each webhook has its own
+// queue, so that events arrive in order, and a failing one is retried with
+// backoff before moving on.
+type hookSender struct {
+	client *http.Client
+	// tries is how many times to try each delivery
+	tries int
+	// delay is the wait before the first retry, doubling each time
+	delay time.Duration
+	// ctx stops everything when the server stops
+	ctx context.Context
+
+	l      sync.Mutex
+	queues map[string]*hookQueue
+}
+
+func newHookSender(ctx context.Context, policy hookPolicy) *hookSender {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: policy.control}
+	return &hookSender{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			// no proxy, as then it would be the proxy's address that's checked
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+		},
+		tries:  5,
+		delay:  time.Second,
+		ctx:    ctx,
+		queues: map[string]*hookQueue{},
+	}
+}
+
+// send queues an event for a webhook. It doesn't block, but drops the event if
+// the webhook has too many waiting.
+func (hs *hookSender) send(h webhook, ev WebhookEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Err(err).Msg("cannot encode webhook event")
+		return
+	}
+
+	// the queue is only closed under the lock, so it's sent to under it too
+	hs.l.Lock()
+	defer hs.l.Unlock()
+
+	q, ok := hs.queues[h.ID]
+	if !ok {
+		ctx, cancel := context.WithCancel(hs.ctx)
+		q = &hookQueue{make(chan hookDelivery, hookQueueSize), ctx, cancel}
+		hs.queues[h.ID] = q
+		go hs.run(q)
+	}
+
+	select {
+	case q.ch <- hookDelivery{h, ev, body}:
+	default:
+		log.Warn().Msgf("webhook %s is backed up, dropping %s", h.ID, ev.ID)
+	}
+}
+
+// remove stops sending to a webhook, dropping anything that's waiting.
+func (hs *hookSender) remove(id string) {
+	hs.l.Lock()
+	defer hs.l.Unlock()
+
+	if q, ok := hs.queues[id]; ok {
+		delete(hs.queues, id)
+		q.cancel()
+	}
+}
+
+// finish stops sending to a webhook once what's waiting has been sent.
+func (hs *hookSender) finish(id string) {
+	hs.l.Lock()
+	defer hs.l.Unlock()
+
+	if q, ok := hs.queues[id]; ok {
+		delete(hs.queues, id)
+		close(q.ch)
+	}
+}
+
+func (hs *hookSender) run(q *hookQueue) {
+	defer q.cancel()
+	for {
+		select {
+		case d, ok := <-q.ch:
+			if !ok {
+				return
+			}
+			hs.deliver(q.ctx, d)
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+func (hs *hookSender) deliver(ctx context.Context, d hookDelivery) {
+	delay := hs.delay
+	for try := 1; ; try++ {
+		err := hs.post(ctx, d.hook, d.ev, d.body)
+		if err == nil {
+			return
+		}
+		if try >= hs.tries {
+			log.Warn().Err(err).Msgf("webhook %s failed, giving up on %s", d.hook.ID, d.ev.ID)
+			return
+		}
+		log.Info().Err(err).Msgf("webhook %s failed, will retry %s", d.hook.ID, d.ev.ID)
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (hs *hookSender) post(ctx context.Context, h webhook, ev WebhookEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gogogo-Event", ev.Event)
+	req.Header.Set("X-Gogogo-Delivery", ev.ID)
+	req.Header.Set("X-Gogogo-Signature", "sha256="+signHook(h.Secret, body))
+
+	res, err := hs.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	ioutil.ReadAll(res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// signHook is the hex HMAC-SHA256 of a body, for receivers to check.
+func signHook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
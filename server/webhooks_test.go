@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/undeconstructed/gogogo/game"
+)
+
+// hookReceiver records events, failing the first few deliveries.
+type hookReceiver struct {
+	t      *testing.T
+	secret string
+	fails  int
+
+	l      sync.Mutex
+	events []WebhookEvent
+	tries  int
+	got    chan struct{}
+}
+
+func (r *hookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.tries++
+	if r.tries <= r.fails {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	if sig := req.Header.Get("X-Gogogo-Signature"); sig != "sha256="+signHook(r.secret, body) {
+		r.t.Errorf("bad signature: %s", sig)
+	}
+	ev := WebhookEvent{}
+	if err := json.Unmarshal(body, &ev); err != nil {
+		r.t.Errorf("bad body: %v", err)
+	}
+	if req.Header.Get("X-Gogogo-Event") != ev.Event {
+		r.t.Errorf("bad event header: %s", req.Header.Get("X-Gogogo-Event"))
+	}
+	r.events = append(r.events, ev)
+	r.got <- struct{}{}
+}
+
+func (r *hookReceiver) wait(t *testing.T, n int) []WebhookEvent {
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.got:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only got %d events", i)
+		}
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	return append([]WebhookEvent(nil), r.events...)
+}
+
+func testHookServer(t *testing.T, fails int) (*server, *hookReceiver) {
+	r := &hookReceiver{t: t, secret: "sssh", fails: fails, got: make(chan struct{}, 10)}
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// the receiver is local, which isn't allowed by default
+	policy, _ := parseHookPolicy("127.0.0.0/8", "")
+	hs := newHookSender(ctx, policy)
+	hs.delay = time.Millisecond
+
+	s := &server{
+		games:      map[string]*instance{},
+		webhooks:   []webhook{{ID: "h", URL: srv.URL, Secret: r.secret}},
+		hookSender: hs,
+	}
+	return s, r
+}
+
+func TestWebhooks_retry(t *testing.T) {
+	s, r := testHookServer(t, 2)
+
+	g := newInstance("go", "g1")
+	s.fireHooks(g, EventGameCreated, "")
+
+	events := r.wait(t, 1)
+	if events[0].Event != EventGameCreated || events[0].Game != "g1" {
+		t.Errorf("bad event: %v", events[0])
+	}
+	if r.tries != 3 {
+		t.Errorf("expected 3 tries, got %d", r.tries)
+	}
+}
+
+func TestWebhooks_state(t *testing.T) {
+	s, r := testHookServer(t, 0)
+
+	g := newInstance("go", "g1")
+	g.meta.Webhooks = []webhook{{ID: "h2", URL: s.webhooks[0].URL, Secret: r.secret, Events: []string{EventGameWon}}}
+	g.state = &game.RGameState{Status: string(game.StatusUnstarted)}
+	s.noteState(g)
+
+	// no change, no event
+	s.noticeChanges(g)
+
+	g.state = &game.RGameState{Status: string(game.StatusInProgress), Playing: "a"}
+	s.noticeChanges(g)
+	events := r.wait(t, 2)
+	if events[0].Event != EventGameStarted || events[1].Event != EventTurn || events[1].Player != "a" {
+		t.Errorf("bad events: %v", events)
+	}
+
+	g.state = &game.RGameState{Status: string(game.StatusWon), Winner: "a"}
+	s.noticeChanges(g)
+	// once for the server hook, once for the game hook
+	events = r.wait(t, 2)
+	for _, ev := range events[2:] {
+		if ev.Event != EventGameWon || ev.Player != "a" {
+			t.Errorf("bad event: %v", ev)
+		}
+	}
+}
+
+func TestWebhooks_remove(t *testing.T) {
+	s, r := testHookServer(t, 0)
+	hs := s.hookSender
+
+	// a removed hook's queue stops at once
+	hs.send(s.webhooks[0], WebhookEvent{ID: "e1", Event: EventGameCreated})
+	r.wait(t, 1)
+	q := hs.queues["h"]
+	hs.remove("h")
+	select {
+	case <-q.ctx.Done():
+	case <-time.After(time.Second):
+		t.Errorf("queue not stopped")
+	}
+
+	// a finished one is sent first
+	for _, id := range []string{"e2", "e3"} {
+		hs.send(s.webhooks[0], WebhookEvent{ID: id, Event: EventGameCreated})
+	}
+	q = hs.queues["h"]
+	hs.finish("h")
+	events := r.wait(t, 2)
+	if len(events) != 3 || events[2].ID != "e3" {
+		t.Errorf("bad events: %v", events)
+	}
+	select {
+	case <-q.ctx.Done():
+	case <-time.After(time.Second):
+		t.Errorf("queue not stopped")
+	}
+	if len(hs.queues) != 0 {
+		t.Errorf("queues left: %v", hs.queues)
+	}
+}
+
+func TestWebhooks_denied(t *testing.T) {
+	s, _ := testHookServer(t, 0)
+
+	// the address is checked when sending too, in case a name has changed
+	hs := newHookSender(context.Background(), hookPolicy{})
+	err := hs.post(context.Background(), s.webhooks[0], WebhookEvent{}, nil)
+	if !errors.Is(err, errHookDenied) {
+		t.Errorf("expected denied, got %v", err)
+	}
+}
+
+func TestWebhookInput_check(t *testing.T) {
+	ctx := context.Background()
+	open, _ := parseHookPolicy("", "")
+	if err := (WebhookInput{URL: "http://93.184.216.34/hook"}).check(ctx, open); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (WebhookInput{URL: "ftp://93.184.216.34/hook"}).check(ctx, open); err == nil {
+		t.Errorf("expected error for bad url")
+	}
+	if err := (WebhookInput{URL: "http://93.184.216.34/hook", Events: []string{"nonsense"}}).check(ctx, open); err == nil {
+		t.Errorf("expected error for bad event")
+	}
+
+	for _, u := range []string{"http://127.0.0.1/hook", "http://10.1.2.3/hook", "http://169.254.169.254/", "http://[::1]:8080/"} {
+		if err := (WebhookInput{URL: u}).check(ctx, open); err != errHookDenied {
+			t.Errorf("expected %s denied, got %v", u, err)
+		}
+	}
+
+	policy, err := parseHookPolicy("10.1.0.0/16", "93.184.216.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (WebhookInput{URL: "http://10.1.2.3/hook"}).check(ctx, policy); err != nil {
+		t.Errorf("expected allowed, got %v", err)
+	}
+	if err := (WebhookInput{URL: "http://93.184.216.34/hook"}).check(ctx, policy); err != errHookDenied {
+		t.Errorf("expected denied, got %v", err)
+	}
+
+	if _, err := parseHookPolicy("nonsense", ""); err == nil {
+		t.Errorf("expected error for bad CIDR")
+	}
+}
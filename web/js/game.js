@@ -21,7 +21,10 @@ function defaultProcessUpdate(u) {
   return u
 }
 
-export function connect(listener, ccode) {
+export function connect(listener, ccode, session) {
+  // session survives reconnects, so that missed messages can be replayed
+  session = session || { resume: '', lastSeq: 0 }
+
   let netState = {
     ws: null,
 
@@ -32,7 +35,12 @@ export function connect(listener, ccode) {
     doRequest() {},
   }
 
-  const conn = new WebSocket(`ws://${location.host}/ws?c=${ccode}`, 'comms')
+  const scheme = location.protocol === 'https:' ? 'wss' : 'ws'
+  let url = `${scheme}://${location.host}/ws?c=${ccode}`
+  if (session.resume) {
+    url += `&resume=${session.resume}&seq=${session.lastSeq}`
+  }
+  const conn = new WebSocket(url, 'comms')
 
   conn.onclose = e => {
     console.log(`WebSocket Disconnected code: ${e.code}, reason: ${e.reason}`)
@@ -40,7 +48,7 @@ export function connect(listener, ccode) {
     listener.onDisconnect()
     if (e.code !== 1001) {
       setTimeout(() => {
-        connect(listener, ccode)
+        connect(listener, ccode, session)
       }, 5000)
     }
   }
@@ -79,8 +87,16 @@ export function connect(listener, ccode) {
     let msg = JSON.parse(e.data)
     console.log('rx', JSON.stringify(msg))
 
+    if (msg.seq) {
+      session.lastSeq = msg.seq
+    }
+
     if (firstMessage) {
       if (msg.head === 'connected') {
+        if (!msg.data.resumed) {
+          session.lastSeq = 0
+        }
+        session.resume = msg.data.resume
         onFullConnect(msg.data.game, msg.data.player, msg.data.colour)
         firstMessage = false
       }